@@ -0,0 +1,82 @@
+package fsys
+
+import (
+	"io"
+	"io/fs"
+	"time"
+)
+
+// fileInfo is a minimal fs.FileInfo implementation shared by drivers that
+// don't have a native os.FileInfo to report (GCS, S3, memory).
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.isDir }
+func (fi *fileInfo) Sys() any           { return nil }
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.isDir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+// dirEntry adapts a fileInfo to fs.DirEntry for ReadDir results.
+type dirEntry struct {
+	info *fileInfo
+}
+
+func (d *dirEntry) Name() string               { return d.info.name }
+func (d *dirEntry) IsDir() bool                { return d.info.isDir }
+func (d *dirEntry) Type() fs.FileMode          { return d.info.Mode().Type() }
+func (d *dirEntry) Info() (fs.FileInfo, error) { return d.info, nil }
+
+// openFile adapts a io.ReadCloser plus its metadata to fs.File so drivers
+// can satisfy fs.FS.Open without implementing a bespoke file type each.
+type openFile struct {
+	io.ReadCloser
+	info *fileInfo
+}
+
+func (f *openFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+// dirFile adapts a synthetic directory fileInfo plus its already-fetched
+// entries to fs.ReadDirFile, so drivers without real directories can satisfy
+// fs.FS.Open for a directory path (notably the root "."), the same way
+// openFile does for a regular file.
+type dirFile struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *dirFile) Close() error { return nil }
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
@@ -1,9 +1,16 @@
 package fsys
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"io/fs"
 	"mime/multipart"
+	"net/url"
 	"os"
+	"path"
+	"sort"
+	"strings"
 )
 
 const (
@@ -14,28 +21,54 @@ const (
 )
 
 // FS defines the methods that any storage system must implement.
+//
+// It embeds the standard io/fs read-side interfaces so that any driver can
+// be handed to fs.WalkDir, text/template.ParseFS, http.FS, and friends.
 type FS interface {
+	fs.FS
+	fs.ReadDirFS
+	fs.StatFS
+	fs.SubFS
+	fs.GlobFS
+
 	// Driver returns the name of the current driver
 	Driver() string
 
 	// Read a file from storage.
 	Read(path string) (io.ReadCloser, error)
 
+	// ReadContext is Read, cancellable via ctx and usable to propagate a
+	// deadline or tracing span from a caller (e.g. an in-flight HTTP
+	// request).
+	ReadContext(ctx context.Context, path string) (io.ReadCloser, error)
+
 	// Write a file to storage.
 	Write(path string, contents []byte) error
 
+	// WriteContext is Write, cancellable via ctx.
+	WriteContext(ctx context.Context, path string, contents []byte) error
+
 	// Delete a file from storage.
 	Delete(path string) error
 
+	// DeleteContext is Delete, cancellable via ctx.
+	DeleteContext(ctx context.Context, path string) error
+
 	// Exists checks if a file exists in storage.
 	Exists(path string) (bool, error)
 
+	// ExistsContext is Exists, cancellable via ctx.
+	ExistsContext(ctx context.Context, path string) (bool, error)
+
 	// Rename a file in storage.
 	Rename(oldPath, newPath string) error
 
 	// Copy a file in storage.
 	Copy(sourcePath, destinationPath string) error
 
+	// CopyContext is Copy, cancellable via ctx.
+	CopyContext(ctx context.Context, sourcePath, destinationPath string) error
+
 	// CreateDirectory creates a new directory if doesn't already exist for the given path
 	CreateDirectory(path string) error
 
@@ -44,9 +77,167 @@ type FS interface {
 	// For example, local storage may return a file path, while cloud storage may return a URL.
 	GetUrl(path string) (string, error)
 
-	// Open opens a file
-	Open(path string) (*os.File, error)
+	// SignedUrl returns a time-limited, method-scoped URL for path, suitable
+	// for handing to a client for a direct upload or download against a
+	// private bucket.
+	SignedUrl(path string, opts SignOptions) (string, error)
+
+	// OpenFile opens a file and returns an *os.File positioned at the start.
+	// GCS and S3 satisfy this by staging the contents into a temp file;
+	// MemoryStorage does not support it and always returns an error.
+	OpenFile(path string) (*os.File, error)
 
 	// Upload uploads a file to the implemented driver
 	Upload(file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error)
+
+	// UploadContext is Upload, cancellable via ctx.
+	UploadContext(ctx context.Context, file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error)
+
+	// List returns every object at or below prefix, recursively, as a flat
+	// slice. Unlike ReadDir, it does not stop at one level. The prefix is a
+	// path boundary: "reports" matches "reports/a.txt" but not
+	// "reports-archive/b.txt".
+	List(prefix string) ([]FileInfo, error)
+
+	// Walk walks the tree rooted at root, calling fn once per directory and
+	// file, in the style of filepath.WalkDir. Drivers without real
+	// directories (GCS, S3, memory) synthesize them from key prefixes.
+	Walk(root string, fn WalkFunc) error
+
+	// DeletePrefix deletes every object at or below prefix, recursively,
+	// treating prefix as a path boundary the same as List.
+	DeletePrefix(prefix string) error
+}
+
+// WriterFile is what CreateFS.Create returns: a handle that can be written
+// to and must be closed to flush/commit the write.
+type WriterFile interface {
+	io.Writer
+	io.Closer
+}
+
+// CreateFS is an optional extension implemented by drivers that support
+// creating/writing a file through the io/fs-shaped API rather than the
+// buffer-at-once Write method.
+type CreateFS interface {
+	Create(name string) (WriterFile, error)
+}
+
+// FromURL builds an FS from a base URL, dispatching on scheme:
+//
+//	file:///var/data         -> local storage rooted at /var/data
+//	gs://bucket/prefix       -> GCS storage rooted at "prefix" within "bucket"
+//	s3://bucket/prefix       -> S3 storage rooted at "prefix" within "bucket"
+//	mem://                   -> a fresh in-memory store
+//
+// Credentials for gs:// and s3:// are taken from the ambient environment
+// (Application Default Credentials / the default AWS config chain); use the
+// driver-specific constructors directly when explicit credentials are
+// required.
+func FromURL(ctx context.Context, base string) (FS, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("fsys: parse %q: %w", base, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		root := u.Path
+		if root == "" {
+			root = u.Opaque
+		}
+		return NewLocalStorage(root)
+
+	case "gs":
+		gcs, err := NewGCSStorageFromEnv(ctx, u.Host)
+		if err != nil {
+			return nil, err
+		}
+		prefix := strings.TrimPrefix(u.Path, "/")
+		if prefix == "" {
+			return gcs, nil
+		}
+		sub, err := gcs.Sub(prefix)
+		if err != nil {
+			return nil, err
+		}
+		return sub.(FS), nil
+
+	case "s3":
+		s3fs, err := NewS3StorageFromEnv(ctx, u.Host)
+		if err != nil {
+			return nil, err
+		}
+		prefix := strings.TrimPrefix(u.Path, "/")
+		if prefix == "" {
+			return s3fs, nil
+		}
+		sub, err := s3fs.Sub(prefix)
+		if err != nil {
+			return nil, err
+		}
+		return sub.(FS), nil
+
+	case "mem":
+		return NewMemoryStorage(), nil
+
+	default:
+		return nil, fmt.Errorf("fsys: unsupported scheme %q in %q", u.Scheme, base)
+	}
+}
+
+// globByReadDir implements fs.GlobFS.Glob in terms of a driver's own ReadDir,
+// so drivers that can't do server-side glob matching (GCS, S3, memory) still
+// get correct pattern matching without recursing back through fs.Glob (which
+// would just call this same method again). A pattern with no wildcard
+// characters is only returned as a match if stat confirms it exists, per the
+// fs.Glob contract ("a nil error means there's no matching file").
+func globByReadDir(readDir func(string) ([]fs.DirEntry, error), stat func(string) (fs.FileInfo, error), pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		if _, err := stat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	var dirs []string
+	if strings.ContainsAny(dir, "*?[") {
+		var err error
+		dirs, err = globByReadDir(readDir, stat, dir)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dirs = []string{dir}
+	}
+
+	var matches []string
+	for _, d := range dirs {
+		entries, err := readDir(d)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			ok, err := path.Match(file, e.Name())
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			if d == "." {
+				matches = append(matches, e.Name())
+			} else {
+				matches = append(matches, d+"/"+e.Name())
+			}
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
 }
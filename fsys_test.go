@@ -0,0 +1,210 @@
+package fsys
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestMemoryStorageWalk(t *testing.T) {
+	ms := NewMemoryStorage()
+	for _, p := range []string{"a.txt", "dir/b.txt", "dir/sub/c.txt"} {
+		if err := ms.Write(p, []byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var visited []string
+	err := ms.Walk(".", func(path string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(visited)
+
+	want := []string{".", "a.txt", "dir", "dir/b.txt", "dir/sub", "dir/sub/c.txt"}
+	sort.Strings(want)
+	if len(visited) != len(want) {
+		t.Fatalf("Walk visited %v, want %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf("Walk visited %v, want %v", visited, want)
+			break
+		}
+	}
+}
+
+func TestMemoryStorageRootIsDirectory(t *testing.T) {
+	ms := NewMemoryStorage()
+	for _, p := range []string{"a.txt", "dir/b.txt"} {
+		if err := ms.Write(p, []byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := ms.Stat(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() {
+		t.Error(`Stat(".") should report a directory`)
+	}
+
+	f, err := ms.Open(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rdf, ok := f.(fs.ReadDirFile)
+	if !ok {
+		t.Fatal(`Open(".") did not return an fs.ReadDirFile`)
+	}
+	entries, err := rdf.ReadDir(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf(`Open(".").ReadDir(-1) = %d entries, want 2`, len(entries))
+	}
+
+	var visited []string
+	err = fs.WalkDir(ms, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(visited)
+	want := []string{".", "a.txt", "dir", "dir/b.txt"}
+	sort.Strings(want)
+	if len(visited) != len(want) {
+		t.Fatalf(`fs.WalkDir(ms, ".") visited %v, want %v`, visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf(`fs.WalkDir(ms, ".") visited %v, want %v`, visited, want)
+			break
+		}
+	}
+}
+
+func TestMemoryStorageGlob(t *testing.T) {
+	ms := NewMemoryStorage()
+	for _, p := range []string{"a.txt", "b.txt", "dir/c.txt"} {
+		if err := ms.Write(p, []byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := ms.Glob("*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(matches)
+	if want := []string{"a.txt", "b.txt"}; len(matches) != len(want) || matches[0] != want[0] || matches[1] != want[1] {
+		t.Errorf(`Glob("*.txt") = %v, want %v`, matches, want)
+	}
+
+	matches, err = ms.Glob("nonexistent.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf(`Glob("nonexistent.txt") = %v, want no matches`, matches)
+	}
+}
+
+func TestMemoryStorageSubIsolatesPrefix(t *testing.T) {
+	ms := NewMemoryStorage()
+	if err := ms.Write("tenants/a/file.txt", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.Write("tenants/b/file.txt", []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	subFS, err := ms.Sub("tenants/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := subFS.(*MemoryStorage)
+
+	rc, err := sub.Read("file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "a" {
+		t.Errorf("sub.Read(file.txt) = %q, want %q", got, "a")
+	}
+
+	entries, err := sub.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name != "file.txt" {
+		t.Errorf("sub.List(\"\") = %v, want only file.txt", entries)
+	}
+}
+
+func TestFromURLFile(t *testing.T) {
+	dir := t.TempDir()
+
+	fsys, err := FromURL(context.Background(), "file://"+dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fsys.Write("hello.txt", []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := filepath.Abs(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := fsys.Read("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hi" {
+		t.Errorf("Read() = %q, want %q", got, "hi")
+	}
+}
+
+func TestFromURLMemory(t *testing.T) {
+	fsys, err := FromURL(context.Background(), "mem://")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fsys.Driver() != DRIVER_MEMORY {
+		t.Errorf("Driver() = %q, want %q", fsys.Driver(), DRIVER_MEMORY)
+	}
+}
+
+func TestFromURLUnsupportedScheme(t *testing.T) {
+	if _, err := FromURL(context.Background(), "ftp://example.com"); err == nil {
+		t.Error("FromURL() with an unsupported scheme should fail")
+	}
+}
@@ -2,13 +2,22 @@ package fsys
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime/multipart"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/storage"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -19,6 +28,55 @@ type GCSStorage struct {
 
 	// GCS client
 	Client *storage.Client
+
+	// httpClient is an authenticated client used for requests the
+	// cloud.google.com/go/storage client doesn't expose, such as the raw
+	// resumable-upload protocol used by NewUploader.
+	httpClient *http.Client
+
+	// prefix is prepended to every path, so a GCSStorage returned by Sub
+	// behaves as if rooted at that prefix within the bucket.
+	prefix string
+
+	// signerEmail and signerKey are the service account identity used to
+	// compute V4 signed URLs locally, without a round trip to GCS. Only set
+	// when constructed via NewGCSStorage, since signing requires a private
+	// key that Application Default Credentials don't expose.
+	signerEmail string
+	signerKey   []byte
+
+	// DefaultChunkSize seeds UploadOptions.ChunkSize for NewUploader calls
+	// that leave it zero. Set by the DRIVER_GCS factory; direct callers of
+	// NewGCSStorage can set it after construction. There is no
+	// DefaultMaxConcurrency: the GCS resumable-upload protocol uploads
+	// chunks sequentially by design, so that knob has no effect here.
+	DefaultChunkSize int64
+
+	// DefaultTimeout bounds how long a Context-suffixed call may run when
+	// the ctx passed in carries no deadline of its own. Zero means no
+	// default: the call runs until ctx is cancelled or the request
+	// completes.
+	DefaultTimeout time.Duration
+}
+
+// withTimeout applies gcs.DefaultTimeout to ctx if set and ctx doesn't
+// already carry a deadline. The returned cancel func must be deferred by
+// the caller even when it's a no-op.
+func (gcs *GCSStorage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if gcs.DefaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, gcs.DefaultTimeout)
+}
+
+// gcsServiceAccountKey is the subset of a GCS service account key JSON file
+// needed to compute signed URLs.
+type gcsServiceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
 }
 
 func NewGCSStorage(projectID, bucket, serviceAccountKey string) (*GCSStorage, error) {
@@ -28,19 +86,85 @@ func NewGCSStorage(projectID, bucket, serviceAccountKey string) (*GCSStorage, er
 		return nil, err
 	}
 
+	keyJSON, err := os.ReadFile(serviceAccountKey)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := google.CredentialsFromJSON(ctx, keyJSON, storage.ScopeReadWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	var sa gcsServiceAccountKey
+	if err := json.Unmarshal(keyJSON, &sa); err != nil {
+		return nil, err
+	}
+
+	return &GCSStorage{
+		BucketName:  bucket,
+		Client:      client,
+		httpClient:  oauth2HTTPClient(ctx, creds),
+		signerEmail: sa.ClientEmail,
+		signerKey:   []byte(sa.PrivateKey),
+	}, nil
+}
+
+// NewGCSStorageFromEnv builds a GCSStorage using Application Default
+// Credentials instead of an explicit service account key file. It's used by
+// FromURL, where a gs:// URL carries no room for credentials.
+func NewGCSStorageFromEnv(ctx context.Context, bucket string) (*GCSStorage, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := google.DefaultClient(ctx, storage.ScopeReadWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	return &GCSStorage{
 		BucketName: bucket,
 		Client:     client,
+		httpClient: httpClient,
 	}, nil
 }
 
+// oauth2HTTPClient builds an *http.Client authenticated with creds, for use
+// with GCS APIs not exposed by the cloud.google.com/go/storage client.
+func oauth2HTTPClient(ctx context.Context, creds *google.Credentials) *http.Client {
+	return oauth2.NewClient(ctx, creds.TokenSource)
+}
+
 func (gcs *GCSStorage) Driver() string {
 	return DRIVER_GCS
 }
 
+// key returns path prefixed with gcs.prefix, as set up by Sub. "." is
+// normalized to "" first, so it resolves to the root (gcs.prefix itself)
+// instead of a literal path segment, the same as fs.ValidPath treats "." as
+// the name of the tree's root.
+func (gcs *GCSStorage) key(p string) string {
+	if p == "." {
+		p = ""
+	}
+	if gcs.prefix == "" {
+		return p
+	}
+	return path.Join(gcs.prefix, p)
+}
+
 func (gcs *GCSStorage) Read(path string) (io.ReadCloser, error) {
-	ctx := context.Background()
-	reader, err := gcs.Client.Bucket(gcs.BucketName).Object(path).NewReader(ctx)
+	return gcs.ReadContext(context.Background(), path)
+}
+
+// ReadContext is Read, threading ctx through to the underlying GCS call so a
+// caller-supplied deadline, cancellation, or tracing span propagates.
+func (gcs *GCSStorage) ReadContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, cancel := gcs.withTimeout(ctx)
+	defer cancel()
+
+	reader, err := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(path)).NewReader(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -48,8 +172,15 @@ func (gcs *GCSStorage) Read(path string) (io.ReadCloser, error) {
 }
 
 func (gcs *GCSStorage) Write(path string, contents []byte) error {
-	ctx := context.Background()
-	writer := gcs.Client.Bucket(gcs.BucketName).Object(path).NewWriter(ctx)
+	return gcs.WriteContext(context.Background(), path, contents)
+}
+
+// WriteContext is Write, threading ctx through to the underlying GCS call.
+func (gcs *GCSStorage) WriteContext(ctx context.Context, path string, contents []byte) error {
+	ctx, cancel := gcs.withTimeout(ctx)
+	defer cancel()
+
+	writer := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(path)).NewWriter(ctx)
 	defer writer.Close()
 
 	_, err := writer.Write(contents)
@@ -60,13 +191,26 @@ func (gcs *GCSStorage) Write(path string, contents []byte) error {
 }
 
 func (gcs *GCSStorage) Delete(path string) error {
-	ctx := context.Background()
-	return gcs.Client.Bucket(gcs.BucketName).Object(path).Delete(ctx)
+	return gcs.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is Delete, threading ctx through to the underlying GCS call.
+func (gcs *GCSStorage) DeleteContext(ctx context.Context, path string) error {
+	ctx, cancel := gcs.withTimeout(ctx)
+	defer cancel()
+	return gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(path)).Delete(ctx)
 }
 
 func (gcs *GCSStorage) Exists(path string) (bool, error) {
-	ctx := context.Background()
-	_, err := gcs.Client.Bucket(gcs.BucketName).Object(path).Attrs(ctx)
+	return gcs.ExistsContext(context.Background(), path)
+}
+
+// ExistsContext is Exists, threading ctx through to the underlying GCS call.
+func (gcs *GCSStorage) ExistsContext(ctx context.Context, path string) (bool, error) {
+	ctx, cancel := gcs.withTimeout(ctx)
+	defer cancel()
+
+	_, err := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(path)).Attrs(ctx)
 	if err != nil {
 		if err == storage.ErrObjectNotExist {
 			return false, nil
@@ -84,16 +228,23 @@ func (gcs *GCSStorage) Rename(oldPath, newPath string) error {
 }
 
 func (gcs *GCSStorage) Copy(sourcePath, destPath string) error {
-	ctx := context.Background()
-	src := gcs.Client.Bucket(gcs.BucketName).Object(sourcePath)
-	dst := gcs.Client.Bucket(gcs.BucketName).Object(destPath)
+	return gcs.CopyContext(context.Background(), sourcePath, destPath)
+}
+
+// CopyContext is Copy, threading ctx through to the underlying GCS call.
+func (gcs *GCSStorage) CopyContext(ctx context.Context, sourcePath, destPath string) error {
+	ctx, cancel := gcs.withTimeout(ctx)
+	defer cancel()
+
+	src := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(sourcePath))
+	dst := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(destPath))
 	_, err := dst.CopierFrom(src).Run(ctx)
 	return err
 }
 
 func (gcs *GCSStorage) CreateDirectory(path string) error {
 	ctx := context.Background()
-	obj := gcs.Client.Bucket(gcs.BucketName).Object(path + "/")
+	obj := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(path) + "/")
 	w := obj.NewWriter(ctx)
 	if err := w.Close(); err != nil {
 		// If the error message indicates that the object already exists, treat it as success
@@ -106,12 +257,48 @@ func (gcs *GCSStorage) CreateDirectory(path string) error {
 }
 
 func (gcs *GCSStorage) GetUrl(path string) (string, error) {
-	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", gcs.BucketName, path), nil
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", gcs.BucketName, gcs.key(path)), nil
 }
 
-func (gcs *GCSStorage) Open(path string) (*os.File, error) {
+// SignedUrl returns a V4 signed URL for path, computed locally from the
+// service account credentials passed to NewGCSStorage. GCSStorage instances
+// built via NewGCSStorageFromEnv (Application Default Credentials) have no
+// private key to sign with and return an error.
+func (gcs *GCSStorage) SignedUrl(path string, opts SignOptions) (string, error) {
+	if gcs.signerEmail == "" || len(gcs.signerKey) == 0 {
+		return "", fmt.Errorf("fsys: signed URLs require a service account key; construct with NewGCSStorage")
+	}
+
+	method := strings.ToUpper(opts.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	sopts := &storage.SignedURLOptions{
+		GoogleAccessID: gcs.signerEmail,
+		PrivateKey:     gcs.signerKey,
+		Method:         method,
+		Expires:        time.Now().Add(opts.Expires),
+		Scheme:         storage.SigningSchemeV4,
+	}
+	if opts.ContentType != "" {
+		sopts.ContentType = opts.ContentType
+	}
+	if opts.ContentMD5 != "" {
+		sopts.MD5 = opts.ContentMD5
+	}
+	if opts.ResponseContentDisposition != "" {
+		sopts.QueryParameters = url.Values{"response-content-disposition": {opts.ResponseContentDisposition}}
+	}
+
+	return gcs.Client.Bucket(gcs.BucketName).SignedURL(gcs.key(path), sopts)
+}
+
+// OpenFile opens a file, staging its contents into a temp *os.File since GCS
+// has no local file handle to hand back.
+func (gcs *GCSStorage) OpenFile(path string) (*os.File, error) {
 	ctx := context.Background()
-	rc, err := gcs.Client.Bucket(gcs.BucketName).Object(path).NewReader(ctx)
+	rc, err := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(path)).NewReader(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -139,9 +326,16 @@ func (gcs *GCSStorage) Open(path string) (*os.File, error) {
 }
 
 func (gcs *GCSStorage) Upload(file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
-	ctx := context.Background()
+	return gcs.UploadContext(context.Background(), file, header, dir)
+}
+
+// UploadContext is Upload, threading ctx through to the underlying GCS call.
+func (gcs *GCSStorage) UploadContext(ctx context.Context, file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	ctx, cancel := gcs.withTimeout(ctx)
+	defer cancel()
+
 	objectPath := fmt.Sprintf("%s/%s", dir, header.Filename)
-	wc := gcs.Client.Bucket(gcs.BucketName).Object(objectPath).NewWriter(ctx)
+	wc := gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(objectPath)).NewWriter(ctx)
 
 	_, err := io.Copy(wc, file)
 	if err != nil {
@@ -153,5 +347,265 @@ func (gcs *GCSStorage) Upload(file multipart.File, header *multipart.FileHeader,
 	}
 
 	// Optionally return the opened file after uploading
-	return gcs.Open(objectPath)
+	return gcs.OpenFile(objectPath)
+}
+
+// Open implements fs.FS, returning an fs.File rather than an *os.File so GCS
+// can be used with fs.WalkDir, text/template.ParseFS, http.FS, etc. Opening
+// the root ("." or "") returns a synthetic directory file backed by
+// ReadDir, since there's no object literally stored under that name.
+func (gcs *GCSStorage) Open(name string) (fs.File, error) {
+	key := gcs.key(name)
+	if key == gcs.prefix {
+		entries, err := gcs.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: &fileInfo{name: ".", isDir: true}, entries: entries}, nil
+	}
+
+	ctx := context.Background()
+	obj := gcs.Client.Bucket(gcs.BucketName).Object(key)
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, err
+	}
+
+	rc, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &openFile{
+		ReadCloser: rc,
+		info: &fileInfo{
+			name:    path.Base(name),
+			size:    attrs.Size,
+			modTime: attrs.Updated,
+		},
+	}, nil
+}
+
+// Stat implements fs.StatFS. The root ("." or "") is synthesized as a
+// directory, since there's no object literally stored under that name.
+func (gcs *GCSStorage) Stat(name string) (fs.FileInfo, error) {
+	key := gcs.key(name)
+	if key == gcs.prefix {
+		return &fileInfo{name: ".", isDir: true}, nil
+	}
+
+	ctx := context.Background()
+	attrs, err := gcs.Client.Bucket(gcs.BucketName).Object(key).Attrs(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, err
+	}
+	return &fileInfo{
+		name:    path.Base(name),
+		size:    attrs.Size,
+		modTime: attrs.Updated,
+	}, nil
+}
+
+// ReadDir implements fs.ReadDirFS by listing objects one level below name,
+// using GCS's delimiter support to fold deeper keys into synthetic
+// directory entries.
+func (gcs *GCSStorage) ReadDir(name string) ([]fs.DirEntry, error) {
+	ctx := context.Background()
+	prefix := gcs.key(name)
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+
+	it := gcs.Client.Bucket(gcs.BucketName).Objects(ctx, &storage.Query{
+		Prefix:    prefix,
+		Delimiter: "/",
+	})
+
+	var entries []fs.DirEntry
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs.Prefix != "" {
+			entries = append(entries, &dirEntry{info: &fileInfo{
+				name:  strings.TrimSuffix(strings.TrimPrefix(attrs.Prefix, prefix), "/"),
+				isDir: true,
+			}})
+			continue
+		}
+		if attrs.Name == prefix {
+			continue
+		}
+		entries = append(entries, &dirEntry{info: &fileInfo{
+			name:    strings.TrimPrefix(attrs.Name, prefix),
+			size:    attrs.Size,
+			modTime: attrs.Updated,
+		}})
+	}
+	return entries, nil
+}
+
+// Glob implements fs.GlobFS.
+func (gcs *GCSStorage) Glob(pattern string) ([]string, error) {
+	return globByReadDir(gcs.ReadDir, gcs.Stat, pattern)
+}
+
+// Sub implements fs.SubFS, returning a GCSStorage rooted at dir within the
+// same bucket.
+func (gcs *GCSStorage) Sub(dir string) (fs.FS, error) {
+	return &GCSStorage{
+		BucketName:       gcs.BucketName,
+		Client:           gcs.Client,
+		httpClient:       gcs.httpClient,
+		prefix:           gcs.key(dir),
+		signerEmail:      gcs.signerEmail,
+		signerKey:        gcs.signerKey,
+		DefaultChunkSize: gcs.DefaultChunkSize,
+		DefaultTimeout:   gcs.DefaultTimeout,
+	}, nil
+}
+
+// Create implements CreateFS, handing back a writer that commits the object
+// on Close.
+func (gcs *GCSStorage) Create(name string) (WriterFile, error) {
+	ctx := context.Background()
+	return gcs.Client.Bucket(gcs.BucketName).Object(gcs.key(name)).NewWriter(ctx), nil
+}
+
+// relativeName strips gcs.prefix from objectName, so List reports paths
+// relative to this GCSStorage's own root the same way key() makes incoming
+// paths relative to it.
+func (gcs *GCSStorage) relativeName(objectName string) string {
+	if gcs.prefix == "" {
+		return objectName
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(objectName, gcs.prefix), "/")
+}
+
+// List returns every object whose key starts with prefix, recursively,
+// using an undelimited Objects query. The prefix is treated as a directory
+// boundary, the same as ReadDir: "reports" matches "reports/a.txt" but not
+// the unrelated sibling key "reports-archive/b.txt". A prefix naming an
+// exact object, e.g. "reports/a.txt", matches that one object, the same as
+// LocalStorage's filepath.WalkDir does when pointed at a file instead of a
+// directory.
+func (gcs *GCSStorage) List(prefix string) ([]FileInfo, error) {
+	ctx := context.Background()
+	p := gcs.key(prefix)
+	it := gcs.Client.Bucket(gcs.BucketName).Objects(ctx, &storage.Query{
+		Prefix: p,
+	})
+
+	var out []FileInfo
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !matchesPrefixBoundary(attrs.Name, p) {
+			continue
+		}
+		out = append(out, FileInfo{
+			Name:        gcs.relativeName(attrs.Name),
+			Size:        attrs.Size,
+			ModTime:     attrs.Updated,
+			ContentType: attrs.ContentType,
+			ETag:        attrs.Etag,
+		})
+	}
+	return out, nil
+}
+
+// Walk walks the tree rooted at root, calling fn once per directory and
+// file, synthesizing directories from key prefixes the same way ReadDir
+// does.
+func (gcs *GCSStorage) Walk(root string, fn WalkFunc) error {
+	return walkByReadDir(gcs.ReadDir, root, fn)
+}
+
+// DeletePrefix deletes every object whose key starts with prefix,
+// recursively. The prefix is treated as a directory boundary, the same as
+// ReadDir: "reports" matches "reports/a.txt" but not the unrelated sibling
+// key "reports-archive/b.txt". A prefix naming an exact object matches that
+// one object, the same as List.
+func (gcs *GCSStorage) DeletePrefix(prefix string) error {
+	ctx := context.Background()
+	p := gcs.key(prefix)
+	it := gcs.Client.Bucket(gcs.BucketName).Objects(ctx, &storage.Query{
+		Prefix: p,
+	})
+
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !matchesPrefixBoundary(attrs.Name, p) {
+			continue
+		}
+		if err := gcs.Client.Bucket(gcs.BucketName).Object(attrs.Name).Delete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GCSParameters documents the New/Register params for DRIVER_GCS.
+type GCSParameters struct {
+	// ProjectID is the GCP project owning the bucket (optional). It is
+	// passed to NewGCSStorage, which does not use it.
+	ProjectID string
+
+	// Bucket is the GCS bucket name (required).
+	Bucket string
+
+	// Credentials is a path to a service account key JSON file (required).
+	Credentials string
+
+	// ChunkSize seeds UploadOptions.ChunkSize for NewUploader calls that
+	// leave it zero (optional). There is no MaxConcurrency: the GCS
+	// resumable-upload protocol uploads chunks sequentially by design.
+	ChunkSize int64
+
+	// Timeout bounds Context-suffixed calls made with a ctx that carries no
+	// deadline of its own (optional).
+	Timeout time.Duration
+}
+
+func init() {
+	Register(DRIVER_GCS, func(params map[string]any) (FS, error) {
+		bucket, err := stringParam(DRIVER_GCS, params, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		credentials, err := stringParam(DRIVER_GCS, params, "credentials")
+		if err != nil {
+			return nil, err
+		}
+		projectID := optionalStringParam(params, "projectID")
+
+		gcs, err := NewGCSStorage(projectID, bucket, credentials)
+		if err != nil {
+			return nil, err
+		}
+		gcs.DefaultChunkSize = optionalInt64Param(params, "chunkSize")
+		gcs.DefaultTimeout = optionalDurationParam(params, "timeout")
+		return gcs, nil
+	})
 }
@@ -0,0 +1,85 @@
+package fsys
+
+import (
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// FileInfo describes an object reported by FS.List and FS.Walk, independent
+// of whichever backend-specific metadata type underlies a given driver.
+type FileInfo struct {
+	Name        string
+	Size        int64
+	ModTime     time.Time
+	ContentType string
+	ETag        string
+}
+
+// WalkFunc is called once per entry visited by FS.Walk, mirroring
+// filepath.WalkDir: a non-nil err reports a failure reading that entry, and
+// fn returning fs.SkipDir skips the rest of the directory it names.
+type WalkFunc func(path string, info FileInfo, err error) error
+
+// matchesPrefixBoundary reports whether key falls under prefix the way
+// List/DeletePrefix define it: either key equals prefix exactly (prefix
+// names a single object, not a directory) or key is nested under it
+// ("prefix/..."). An empty prefix matches every key. This keeps List and
+// DeletePrefix consistent with LocalStorage, where filepath.WalkDir applied
+// to an exact file path naturally returns that one file.
+func matchesPrefixBoundary(key, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	return key == prefix || strings.HasPrefix(key, prefix+"/")
+}
+
+// walkByReadDir implements FS.Walk in terms of a driver's own ReadDir, the
+// same way globByReadDir implements Glob: drivers without real directories
+// (GCS, S3, memory) get a correct, hierarchical walk without each
+// reimplementing the recursion.
+func walkByReadDir(readDir func(string) ([]fs.DirEntry, error), root string, fn WalkFunc) error {
+	entries, err := readDir(root)
+	if err != nil {
+		return fn(root, FileInfo{Name: root}, err)
+	}
+
+	if err := fn(root, FileInfo{Name: root}, nil); err != nil {
+		if err == fs.SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	for _, e := range entries {
+		entryPath := strings.TrimSuffix(root, "/")
+		if entryPath == "" || entryPath == "." {
+			entryPath = e.Name()
+		} else {
+			entryPath += "/" + e.Name()
+		}
+
+		if e.IsDir() {
+			if err := walkByReadDir(readDir, entryPath, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			if walkErr := fn(entryPath, FileInfo{Name: e.Name()}, err); walkErr != nil {
+				return walkErr
+			}
+			continue
+		}
+
+		if walkErr := fn(entryPath, FileInfo{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()}, nil); walkErr != nil {
+			if walkErr == fs.SkipDir {
+				continue
+			}
+			return walkErr
+		}
+	}
+	return nil
+}
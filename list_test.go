@@ -0,0 +1,74 @@
+package fsys
+
+import "testing"
+
+// TestListExactFilePrefixMatchesAcrossDrivers locks in that a prefix naming
+// an exact file, not a directory, returns that one file the same way on
+// every driver: LocalStorage's filepath.WalkDir already does this naturally
+// when pointed at a file, and List/DeletePrefix on the other drivers must
+// agree rather than treating the prefix as a directory boundary only.
+func TestListExactFilePrefixMatchesAcrossDrivers(t *testing.T) {
+	ms := NewMemoryStorage()
+	if err := ms.Write("reports/a.txt", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	ls, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.Write("reports/a.txt", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fs := range []FS{ms, ls} {
+		got, err := fs.List("reports/a.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Name != "reports/a.txt" {
+			t.Errorf("%T.List(%q) = %v, want only reports/a.txt", fs, "reports/a.txt", got)
+		}
+	}
+}
+
+func TestMemoryStorageListPrefixBoundary(t *testing.T) {
+	ms := NewMemoryStorage()
+
+	if err := ms.Write("reports/a.txt", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.Write("reports-archive/b.txt", []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ms.List("reports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "reports/a.txt" {
+		t.Fatalf("List(%q) = %v, want only reports/a.txt", "reports", got)
+	}
+}
+
+func TestMemoryStorageDeletePrefixBoundary(t *testing.T) {
+	ms := NewMemoryStorage()
+
+	if err := ms.Write("reports/a.txt", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.Write("reports-archive/b.txt", []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ms.DeletePrefix("reports"); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := ms.Exists("reports/a.txt"); exists {
+		t.Error("reports/a.txt should have been deleted")
+	}
+	if exists, _ := ms.Exists("reports-archive/b.txt"); !exists {
+		t.Error("reports-archive/b.txt should not have been deleted")
+	}
+}
@@ -0,0 +1,322 @@
+package fsys
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage is an implementation of FS backed by the local filesystem,
+// rooted at a single directory.
+type LocalStorage struct {
+	// rootDir is the absolute directory every path is resolved relative to.
+	rootDir string
+}
+
+// NewLocalStorage returns a LocalStorage rooted at rootDir, creating it if
+// it doesn't already exist.
+func NewLocalStorage(rootDir string) (*LocalStorage, error) {
+	abs, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{rootDir: abs}, nil
+}
+
+func (ls *LocalStorage) Driver() string {
+	return DRIVER_LOCAL
+}
+
+// path resolves p to an absolute path within rootDir.
+func (ls *LocalStorage) path(p string) string {
+	return filepath.Join(ls.rootDir, p)
+}
+
+func (ls *LocalStorage) Read(path string) (io.ReadCloser, error) {
+	return ls.ReadContext(context.Background(), path)
+}
+
+// ReadContext is Read, cancellable via ctx before the file is opened.
+func (ls *LocalStorage) ReadContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return os.Open(ls.path(path))
+}
+
+func (ls *LocalStorage) Write(path string, contents []byte) error {
+	return ls.WriteContext(context.Background(), path, contents)
+}
+
+// WriteContext is Write, cancellable via ctx before the file is written.
+func (ls *LocalStorage) WriteContext(ctx context.Context, path string, contents []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	full := ls.path(path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(full, contents, 0644)
+}
+
+func (ls *LocalStorage) Delete(path string) error {
+	return ls.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is Delete, cancellable via ctx before the file is removed.
+func (ls *LocalStorage) DeleteContext(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return os.Remove(ls.path(path))
+}
+
+func (ls *LocalStorage) Exists(path string) (bool, error) {
+	return ls.ExistsContext(context.Background(), path)
+}
+
+// ExistsContext is Exists, cancellable via ctx before the stat call.
+func (ls *LocalStorage) ExistsContext(ctx context.Context, path string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(ls.path(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (ls *LocalStorage) Rename(oldPath, newPath string) error {
+	full := ls.path(newPath)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return err
+	}
+	return os.Rename(ls.path(oldPath), full)
+}
+
+func (ls *LocalStorage) Copy(sourcePath, destinationPath string) error {
+	return ls.CopyContext(context.Background(), sourcePath, destinationPath)
+}
+
+// CopyContext is Copy, cancellable via ctx before the copy starts.
+func (ls *LocalStorage) CopyContext(ctx context.Context, sourcePath, destinationPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	in, err := os.Open(ls.path(sourcePath))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	dest := ls.path(destinationPath)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func (ls *LocalStorage) CreateDirectory(path string) error {
+	return os.MkdirAll(ls.path(path), 0755)
+}
+
+func (ls *LocalStorage) GetUrl(path string) (string, error) {
+	return "file://" + ls.path(path), nil
+}
+
+// SignedUrl returns a mock signed URL, since local storage has no real
+// signing backend. The method/expiration/content bindings are encoded as
+// query parameters so callers can still exercise signing semantics in
+// tests.
+func (ls *LocalStorage) SignedUrl(path string, opts SignOptions) (string, error) {
+	return mockSignedUrl("file://"+ls.path(path), opts), nil
+}
+
+// OpenFile opens a file directly off disk.
+func (ls *LocalStorage) OpenFile(path string) (*os.File, error) {
+	return os.Open(ls.path(path))
+}
+
+func (ls *LocalStorage) Upload(file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	return ls.UploadContext(context.Background(), file, header, dir)
+}
+
+// UploadContext is Upload, cancellable via ctx before the upload starts.
+func (ls *LocalStorage) UploadContext(ctx context.Context, file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	relPath := filepath.Join(dir, header.Filename)
+	full := ls.path(relPath)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return nil, err
+	}
+
+	out, err := os.Create(full)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, file); err != nil {
+		return nil, err
+	}
+
+	return ls.OpenFile(relPath)
+}
+
+// Open implements fs.FS. *os.File already satisfies fs.File.
+func (ls *LocalStorage) Open(name string) (fs.File, error) {
+	return os.Open(ls.path(name))
+}
+
+// Stat implements fs.StatFS.
+func (ls *LocalStorage) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(ls.path(name))
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (ls *LocalStorage) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(ls.path(name))
+}
+
+// Glob implements fs.GlobFS.
+func (ls *LocalStorage) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(ls.path(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	rel := make([]string, len(matches))
+	for i, m := range matches {
+		r, err := filepath.Rel(ls.rootDir, m)
+		if err != nil {
+			return nil, err
+		}
+		rel[i] = filepath.ToSlash(r)
+	}
+	return rel, nil
+}
+
+// Sub implements fs.SubFS, returning a LocalStorage rooted at dir.
+func (ls *LocalStorage) Sub(dir string) (fs.FS, error) {
+	return NewLocalStorage(ls.path(dir))
+}
+
+// Create implements CreateFS. *os.File already satisfies WriterFile.
+func (ls *LocalStorage) Create(name string) (WriterFile, error) {
+	full := ls.path(name)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(full)
+}
+
+// List returns every regular file at or below prefix, recursively. A
+// missing prefix is treated as an empty listing rather than an error, the
+// same as a bucket-backed driver would report no matching keys.
+func (ls *LocalStorage) List(prefix string) ([]FileInfo, error) {
+	var out []FileInfo
+	err := filepath.WalkDir(ls.path(prefix), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(ls.rootDir, p)
+		if err != nil {
+			return err
+		}
+		out = append(out, FileInfo{
+			Name:        filepath.ToSlash(rel),
+			Size:        info.Size(),
+			ModTime:     info.ModTime(),
+			ContentType: mime.TypeByExtension(filepath.Ext(p)),
+		})
+		return nil
+	})
+	if err != nil && os.IsNotExist(err) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Walk walks the tree rooted at root, calling fn once per directory and
+// file in lexical order, the same as filepath.WalkDir.
+func (ls *LocalStorage) Walk(root string, fn WalkFunc) error {
+	return filepath.WalkDir(ls.path(root), func(p string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(ls.rootDir, p)
+		if relErr != nil {
+			rel = p
+		}
+		rel = filepath.ToSlash(rel)
+
+		if err != nil {
+			return fn(rel, FileInfo{Name: rel}, err)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fn(rel, FileInfo{Name: rel}, err)
+		}
+
+		fi := FileInfo{Name: rel, ModTime: info.ModTime()}
+		if !d.IsDir() {
+			fi.Size = info.Size()
+			fi.ContentType = mime.TypeByExtension(filepath.Ext(p))
+		}
+		return fn(rel, fi, nil)
+	})
+}
+
+// DeletePrefix removes prefix and everything under it.
+func (ls *LocalStorage) DeletePrefix(prefix string) error {
+	return os.RemoveAll(ls.path(prefix))
+}
+
+// LocalParameters documents the New/Register params for DRIVER_LOCAL.
+type LocalParameters struct {
+	// RootDirectory is the directory LocalStorage is rooted at (required).
+	RootDirectory string
+}
+
+func init() {
+	Register(DRIVER_LOCAL, func(params map[string]any) (FS, error) {
+		rootDirectory, err := stringParam(DRIVER_LOCAL, params, "rootDirectory")
+		if err != nil {
+			return nil, err
+		}
+		return NewLocalStorage(rootDirectory)
+	})
+}
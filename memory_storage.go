@@ -2,45 +2,88 @@ package fsys
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"io/fs"
 	"mime/multipart"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
-// MemoryStorage is an in-memory implementation of the FS interface.
-type MemoryStorage struct {
+// memoryCore holds the state shared by a MemoryStorage and every MemoryStorage
+// returned from its Sub, so they all see the same underlying files.
+type memoryCore struct {
 	files map[string]*File
 	mu    sync.RWMutex
+
+	// sessions tracks in-progress resumable uploads started via NewUploader,
+	// keyed by the resume token handed back from Uploader.Token.
+	sessions map[string]*memorySession
+}
+
+// MemoryStorage is an in-memory implementation of the FS interface.
+type MemoryStorage struct {
+	core *memoryCore
+
+	// prefix is prepended to every path, so a MemoryStorage returned by Sub
+	// behaves as if rooted at that prefix.
+	prefix string
 }
 
 // File represents an in-memory file.
 type File struct {
 	Name    string
 	Content *bytes.Buffer
+	ModTime time.Time
 }
 
 // NewMemoryStorage returns a new MemoryStorage instance.
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		files: make(map[string]*File),
+		core: &memoryCore{files: make(map[string]*File)},
 	}
 }
 
 // Driver returns the name of the current driver.
-func (fs *MemoryStorage) Driver() string {
+func (ms *MemoryStorage) Driver() string {
 	return DRIVER_MEMORY
 }
 
+// key returns path prefixed with ms.prefix, as set up by Sub. "." is
+// normalized to "" first, so it resolves to the root (ms.prefix itself)
+// instead of a literal path segment, the same as fs.ValidPath treats "." as
+// the name of the tree's root.
+func (ms *MemoryStorage) key(p string) string {
+	if p == "." {
+		p = ""
+	}
+	if ms.prefix == "" {
+		return p
+	}
+	return path.Join(ms.prefix, p)
+}
+
 // Read reads a file from memory.
-func (fs *MemoryStorage) Read(path string) (io.ReadCloser, error) {
-	fs.mu.RLock()
-	defer fs.mu.RUnlock()
+func (ms *MemoryStorage) Read(path string) (io.ReadCloser, error) {
+	return ms.ReadContext(context.Background(), path)
+}
+
+// ReadContext is Read, cancellable via ctx before the read.
+func (ms *MemoryStorage) ReadContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ms.core.mu.RLock()
+	defer ms.core.mu.RUnlock()
 
-	file, exists := fs.files[path]
+	file, exists := ms.core.files[ms.key(path)]
 	if !exists {
 		return nil, os.ErrNotExist
 	}
@@ -48,74 +91,115 @@ func (fs *MemoryStorage) Read(path string) (io.ReadCloser, error) {
 }
 
 // Write writes a file to memory.
-func (fs *MemoryStorage) Write(path string, contents []byte) error {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
+func (ms *MemoryStorage) Write(path string, contents []byte) error {
+	return ms.WriteContext(context.Background(), path, contents)
+}
 
-	fs.files[path] = &File{
-		Name:    path,
+// WriteContext is Write, cancellable via ctx before the write.
+func (ms *MemoryStorage) WriteContext(ctx context.Context, path string, contents []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
+
+	ms.core.files[ms.key(path)] = &File{
+		Name:    ms.key(path),
 		Content: bytes.NewBuffer(contents),
+		ModTime: time.Now(),
 	}
 	return nil
 }
 
 // Delete deletes a file from memory.
-func (fs *MemoryStorage) Delete(path string) error {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
+func (ms *MemoryStorage) Delete(path string) error {
+	return ms.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is Delete, cancellable via ctx before the delete.
+func (ms *MemoryStorage) DeleteContext(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	if _, exists := fs.files[path]; !exists {
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
+
+	key := ms.key(path)
+	if _, exists := ms.core.files[key]; !exists {
 		return os.ErrNotExist
 	}
-	delete(fs.files, path)
+	delete(ms.core.files, key)
 	return nil
 }
 
 // Exists checks if a file exists in memory.
-func (fs *MemoryStorage) Exists(path string) (bool, error) {
-	fs.mu.RLock()
-	defer fs.mu.RUnlock()
+func (ms *MemoryStorage) Exists(path string) (bool, error) {
+	return ms.ExistsContext(context.Background(), path)
+}
+
+// ExistsContext is Exists, cancellable via ctx before the lookup.
+func (ms *MemoryStorage) ExistsContext(ctx context.Context, path string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	ms.core.mu.RLock()
+	defer ms.core.mu.RUnlock()
 
-	_, exists := fs.files[path]
+	_, exists := ms.core.files[ms.key(path)]
 	return exists, nil
 }
 
 // Rename renames a file in memory.
-func (fs *MemoryStorage) Rename(oldPath, newPath string) error {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
+func (ms *MemoryStorage) Rename(oldPath, newPath string) error {
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
 
-	file, exists := fs.files[oldPath]
+	oldKey, newKey := ms.key(oldPath), ms.key(newPath)
+	file, exists := ms.core.files[oldKey]
 	if !exists {
 		return os.ErrNotExist
 	}
 
 	// Perform the rename
-	fs.files[newPath] = file
-	delete(fs.files, oldPath)
+	ms.core.files[newKey] = file
+	delete(ms.core.files, oldKey)
 	return nil
 }
 
 // Copy copies a file in memory.
-func (fs *MemoryStorage) Copy(sourcePath, destinationPath string) error {
-	fs.mu.RLock()
-	defer fs.mu.RUnlock()
+func (ms *MemoryStorage) Copy(sourcePath, destinationPath string) error {
+	return ms.CopyContext(context.Background(), sourcePath, destinationPath)
+}
 
-	sourceFile, exists := fs.files[sourcePath]
+// CopyContext is Copy, cancellable via ctx before the copy.
+func (ms *MemoryStorage) CopyContext(ctx context.Context, sourcePath, destinationPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
+
+	sourceFile, exists := ms.core.files[ms.key(sourcePath)]
 	if !exists {
 		return os.ErrNotExist
 	}
 
 	// Copy content
-	fs.files[destinationPath] = &File{
-		Name:    destinationPath,
+	destKey := ms.key(destinationPath)
+	ms.core.files[destKey] = &File{
+		Name:    destKey,
 		Content: bytes.NewBuffer(sourceFile.Content.Bytes()),
+		ModTime: time.Now(),
 	}
 	return nil
 }
 
 // CreateDirectory is a no-op for memory storage but can simulate directory creation.
-func (fs *MemoryStorage) CreateDirectory(path string) error {
+func (ms *MemoryStorage) CreateDirectory(path string) error {
 	// Since this is in-memory storage, directory creation can be simulated as a prefix check.
 	if !strings.HasSuffix(path, "/") {
 		return errors.New("directory path must end with '/'")
@@ -124,23 +208,45 @@ func (fs *MemoryStorage) CreateDirectory(path string) error {
 }
 
 // GetUrl returns a mock URL for memory-stored files.
-func (fs *MemoryStorage) GetUrl(path string) (string, error) {
+func (ms *MemoryStorage) GetUrl(path string) (string, error) {
 	// In-memory storage doesn't have real URLs, so return a mock URL.
-	if exists, _ := fs.Exists(path); !exists {
+	if exists, _ := ms.Exists(path); !exists {
 		return "", os.ErrNotExist
 	}
-	return "mem://" + path, nil
+	return "mem://" + ms.key(path), nil
+}
+
+// SignedUrl returns a mock signed URL, since in-memory storage has no real
+// signing backend. The method/expiration/content bindings are encoded as
+// query parameters so callers can still exercise signing semantics in
+// tests.
+func (ms *MemoryStorage) SignedUrl(path string, opts SignOptions) (string, error) {
+	if strings.ToUpper(opts.Method) != http.MethodPut {
+		if exists, _ := ms.Exists(path); !exists {
+			return "", os.ErrNotExist
+		}
+	}
+	return mockSignedUrl("mem://"+ms.key(path), opts), nil
 }
 
-// Open opens a file (not fully applicable for memory but returns a mock).
-func (fs *MemoryStorage) Open(path string) (*os.File, error) {
+// OpenFile is not fully applicable for memory storage; it errors.
+func (ms *MemoryStorage) OpenFile(path string) (*os.File, error) {
 	return nil, errors.New("open is not supported for in-memory storage")
 }
 
 // Upload simulates file upload by storing the uploaded file's content.
-func (fs *MemoryStorage) Upload(file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
+func (ms *MemoryStorage) Upload(file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	return ms.UploadContext(context.Background(), file, header, dir)
+}
+
+// UploadContext is Upload, cancellable via ctx before the upload.
+func (ms *MemoryStorage) UploadContext(ctx context.Context, file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
 
 	var buf bytes.Buffer
 	_, err := io.Copy(&buf, file)
@@ -149,12 +255,216 @@ func (fs *MemoryStorage) Upload(file multipart.File, header *multipart.FileHeade
 	}
 
 	// Simulate storing the uploaded file in memory
-	filePath := filepath.Join(dir, header.Filename)
-	fs.files[filePath] = &File{
+	filePath := ms.key(filepath.Join(dir, header.Filename))
+	ms.core.files[filePath] = &File{
 		Name:    filePath,
 		Content: &buf,
+		ModTime: time.Now(),
 	}
 
 	// Since this is in-memory, there's no real os.File to return.
 	return nil, nil
 }
+
+// Open implements fs.FS. Opening the root ("." or "") returns a synthetic
+// directory file backed by ReadDir, since the flat key space has no object
+// literally stored under that name.
+func (ms *MemoryStorage) Open(name string) (fs.File, error) {
+	key := ms.key(name)
+	if key == ms.prefix {
+		entries, err := ms.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: &fileInfo{name: ".", isDir: true}, entries: entries}, nil
+	}
+
+	ms.core.mu.RLock()
+	defer ms.core.mu.RUnlock()
+
+	file, exists := ms.core.files[key]
+	if !exists {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &openFile{
+		ReadCloser: io.NopCloser(bytes.NewReader(file.Content.Bytes())),
+		info: &fileInfo{
+			name:    path.Base(name),
+			size:    int64(file.Content.Len()),
+			modTime: file.ModTime,
+		},
+	}, nil
+}
+
+// Stat implements fs.StatFS. The root ("." or "") is synthesized as a
+// directory, since the flat key space has no object literally stored under
+// that name.
+func (ms *MemoryStorage) Stat(name string) (fs.FileInfo, error) {
+	key := ms.key(name)
+	if key == ms.prefix {
+		return &fileInfo{name: ".", isDir: true}, nil
+	}
+
+	ms.core.mu.RLock()
+	defer ms.core.mu.RUnlock()
+
+	file, exists := ms.core.files[key]
+	if !exists {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return &fileInfo{
+		name:    path.Base(name),
+		size:    int64(file.Content.Len()),
+		modTime: file.ModTime,
+	}, nil
+}
+
+// ReadDir implements fs.ReadDirFS by scanning the flat key space for entries
+// directly below name.
+func (ms *MemoryStorage) ReadDir(name string) ([]fs.DirEntry, error) {
+	ms.core.mu.RLock()
+	defer ms.core.mu.RUnlock()
+
+	prefix := ms.key(name)
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+
+	seen := map[string]*fs.DirEntry{}
+	var order []string
+	for key, file := range ms.core.files {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(key, prefix)
+		if rest == "" {
+			continue
+		}
+		if idx := strings.Index(rest, "/"); idx >= 0 {
+			dirName := rest[:idx]
+			if _, ok := seen[dirName]; !ok {
+				var e fs.DirEntry = &dirEntry{info: &fileInfo{name: dirName, isDir: true}}
+				seen[dirName] = &e
+				order = append(order, dirName)
+			}
+			continue
+		}
+		if _, ok := seen[rest]; !ok {
+			var e fs.DirEntry = &dirEntry{info: &fileInfo{
+				name:    rest,
+				size:    int64(file.Content.Len()),
+				modTime: file.ModTime,
+			}}
+			seen[rest] = &e
+			order = append(order, rest)
+		}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(order))
+	for _, name := range order {
+		entries = append(entries, *seen[name])
+	}
+	return entries, nil
+}
+
+// Glob implements fs.GlobFS.
+func (ms *MemoryStorage) Glob(pattern string) ([]string, error) {
+	return globByReadDir(ms.ReadDir, ms.Stat, pattern)
+}
+
+// Sub implements fs.SubFS, returning a MemoryStorage rooted at dir within
+// the same backing store.
+func (ms *MemoryStorage) Sub(dir string) (fs.FS, error) {
+	return &MemoryStorage{core: ms.core, prefix: ms.key(dir)}, nil
+}
+
+// Create implements CreateFS.
+func (ms *MemoryStorage) Create(name string) (WriterFile, error) {
+	return &memoryWriter{ms: ms, name: name, buf: &bytes.Buffer{}}, nil
+}
+
+// memoryWriter buffers writes and commits them to the store on Close.
+type memoryWriter struct {
+	ms   *MemoryStorage
+	name string
+	buf  *bytes.Buffer
+}
+
+func (w *memoryWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *memoryWriter) Close() error {
+	return w.ms.Write(w.name, w.buf.Bytes())
+}
+
+// relativeName strips ms.prefix from key, so List reports paths relative to
+// this MemoryStorage's own root the same way key() makes incoming paths
+// relative to it.
+func (ms *MemoryStorage) relativeName(key string) string {
+	if ms.prefix == "" {
+		return key
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(key, ms.prefix), "/")
+}
+
+// List returns every file whose key starts with prefix, recursively. The
+// prefix is treated as a directory boundary, the same as ReadDir: "reports"
+// matches "reports/a.txt" but not the unrelated sibling key
+// "reports-archive/b.txt". A prefix naming an exact file, e.g.
+// "reports/a.txt", matches that one file, the same as LocalStorage's
+// filepath.WalkDir does when pointed at a file instead of a directory.
+func (ms *MemoryStorage) List(prefix string) ([]FileInfo, error) {
+	ms.core.mu.RLock()
+	defer ms.core.mu.RUnlock()
+
+	p := ms.key(prefix)
+	var out []FileInfo
+	for key, file := range ms.core.files {
+		if !matchesPrefixBoundary(key, p) {
+			continue
+		}
+		out = append(out, FileInfo{
+			Name:    ms.relativeName(key),
+			Size:    int64(file.Content.Len()),
+			ModTime: file.ModTime,
+		})
+	}
+	return out, nil
+}
+
+// Walk walks the tree rooted at root, calling fn once per directory and
+// file, synthesizing directories from key prefixes the same way ReadDir
+// does.
+func (ms *MemoryStorage) Walk(root string, fn WalkFunc) error {
+	return walkByReadDir(ms.ReadDir, root, fn)
+}
+
+// DeletePrefix deletes every file whose key starts with prefix,
+// recursively. The prefix is treated as a directory boundary, the same as
+// ReadDir: "reports" matches "reports/a.txt" but not the unrelated sibling
+// key "reports-archive/b.txt". A prefix naming an exact file matches that
+// one file, the same as List.
+func (ms *MemoryStorage) DeletePrefix(prefix string) error {
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
+
+	p := ms.key(prefix)
+	for key := range ms.core.files {
+		if matchesPrefixBoundary(key, p) {
+			delete(ms.core.files, key)
+		}
+	}
+	return nil
+}
+
+// MemoryParameters documents the New/Register params for DRIVER_MEMORY.
+// MemoryStorage takes no required configuration.
+type MemoryParameters struct{}
+
+func init() {
+	Register(DRIVER_MEMORY, func(params map[string]any) (FS, error) {
+		return NewMemoryStorage(), nil
+	})
+}
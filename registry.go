@@ -0,0 +1,127 @@
+package fsys
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DriverFactory builds an FS from driver-specific params, typically decoded
+// from a YAML/JSON config at application startup.
+type DriverFactory func(params map[string]any) (FS, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]DriverFactory)
+)
+
+// Register makes a driver factory available under name, so New can build an
+// FS without the caller importing the driver-specific constructor. Drivers
+// built into this package self-register from init(); Register panics if
+// called twice with the same name, the same as database/sql's driver
+// registry.
+func Register(name string, factory DriverFactory) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if factory == nil {
+		panic("fsys: Register factory is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("fsys: Register called twice for driver " + name)
+	}
+	drivers[name] = factory
+}
+
+// New builds an FS using the factory registered under name (one of
+// DRIVER_LOCAL, DRIVER_GCS, DRIVER_S3, DRIVER_MEMORY, or a name Register was
+// called with directly), validating params the same way the matching
+// driver-specific constructor would.
+func New(name string, params map[string]any) (FS, error) {
+	driversMu.RLock()
+	factory, ok := drivers[name]
+	driversMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("fsys: unknown driver %q (forgotten import?)", name)
+	}
+	return factory(params)
+}
+
+// ParamError reports a missing or invalid New/Register parameter, letting
+// callers (e.g. a config loader) distinguish misconfiguration from a
+// runtime storage failure.
+type ParamError struct {
+	Driver string
+	Key    string
+	Reason string
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("fsys: %s driver: parameter %q: %s", e.Driver, e.Key, e.Reason)
+}
+
+// stringParam reads key from params as a required, non-empty string.
+func stringParam(driver string, params map[string]any, key string) (string, error) {
+	v, ok := params[key].(string)
+	if !ok || v == "" {
+		return "", &ParamError{Driver: driver, Key: key, Reason: "required non-empty string"}
+	}
+	return v, nil
+}
+
+// optionalStringParam reads key from params as an optional string,
+// returning "" if absent.
+func optionalStringParam(params map[string]any, key string) string {
+	v, _ := params[key].(string)
+	return v
+}
+
+// optionalInt64Param reads key from params as an optional int64, accepting
+// an int64, an int, or a float64 (as JSON/YAML decoders commonly produce for
+// a bare number decoded into map[string]any), and returning 0 if absent.
+func optionalInt64Param(params map[string]any, key string) int64 {
+	switch v := params[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// optionalIntParam reads key from params as an optional int, accepting an
+// int or a float64 (as JSON/YAML decoders commonly produce for a bare
+// number decoded into map[string]any), and returning 0 if absent.
+func optionalIntParam(params map[string]any, key string) int {
+	switch v := params[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// optionalDurationParam reads key from params as an optional time.Duration,
+// accepting either a time.Duration directly or a string parsed with
+// time.ParseDuration (e.g. "30s", as config decoders commonly produce), and
+// returning 0 if absent or unparseable.
+func optionalDurationParam(params map[string]any, key string) time.Duration {
+	switch v := params[key].(type) {
+	case time.Duration:
+		return v
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0
+		}
+		return d
+	default:
+		return 0
+	}
+}
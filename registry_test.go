@@ -0,0 +1,50 @@
+package fsys
+
+import "testing"
+
+func TestNewMemoryDriver(t *testing.T) {
+	fsys, err := New(DRIVER_MEMORY, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fsys.Driver() != DRIVER_MEMORY {
+		t.Errorf("Driver() = %q, want %q", fsys.Driver(), DRIVER_MEMORY)
+	}
+}
+
+func TestNewLocalDriver(t *testing.T) {
+	dir := t.TempDir()
+	fsys, err := New(DRIVER_LOCAL, map[string]any{"rootDirectory": dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fsys.Driver() != DRIVER_LOCAL {
+		t.Errorf("Driver() = %q, want %q", fsys.Driver(), DRIVER_LOCAL)
+	}
+}
+
+func TestNewLocalDriverMissingRequiredParam(t *testing.T) {
+	if _, err := New(DRIVER_LOCAL, nil); err == nil {
+		t.Error("New(DRIVER_LOCAL, nil) should fail without a rootDirectory param")
+	}
+}
+
+func TestNewUnknownDriver(t *testing.T) {
+	if _, err := New("not-a-real-driver", nil); err == nil {
+		t.Error("New() with an unregistered driver name should fail")
+	}
+}
+
+func TestOptionalInt64ParamAcceptsFloat64(t *testing.T) {
+	params := map[string]any{"chunkSize": float64(1048576)}
+	if got := optionalInt64Param(params, "chunkSize"); got != 1048576 {
+		t.Errorf("optionalInt64Param() = %d, want 1048576", got)
+	}
+}
+
+func TestOptionalIntParamAcceptsFloat64(t *testing.T) {
+	params := map[string]any{"maxConcurrency": float64(8)}
+	if got := optionalIntParam(params, "maxConcurrency"); got != 8 {
+		t.Errorf("optionalIntParam() = %d, want 8", got)
+	}
+}
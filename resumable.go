@@ -0,0 +1,74 @@
+package fsys
+
+import (
+	"math/rand"
+	"time"
+)
+
+// UploadOptions configures a resumable/chunked upload started via
+// ResumableFS.NewUploader.
+type UploadOptions struct {
+	// ContentType is the MIME type of the uploaded object (optional).
+	ContentType string
+
+	// ChunkSize is the size, in bytes, of each chunk sent to the backend.
+	// Zero means "use the driver's default". GCS requires chunk sizes to be
+	// a multiple of 256 KiB; NewUploader rounds up to satisfy that.
+	ChunkSize int64
+
+	// MaxConcurrency bounds how many parts may be in flight to the backend
+	// at once. Zero means the driver's default (S3Storage.DefaultMaxConcurrency),
+	// and sequential (one at a time) when that is unset too. Only S3Storage honors
+	// this: S3 multipart parts are independent and can upload concurrently,
+	// while GCS's resumable-upload protocol addresses each chunk by the
+	// byte offset the previous one ended at, so GCSStorage always uploads
+	// sequentially regardless of this setting.
+	MaxConcurrency int
+}
+
+// Uploader is a single in-progress chunked upload. Callers write to it like
+// any io.Writer, then either Commit to finalize the object or Abort to
+// cancel the upload and release backend-side resources.
+type Uploader interface {
+	// Write buffers and, once a full chunk has accumulated, uploads p.
+	Write(p []byte) (int, error)
+
+	// Commit flushes any remaining buffered bytes and finalizes the upload.
+	Commit() error
+
+	// Abort cancels the upload, releasing any backend-side session/parts.
+	Abort() error
+
+	// Token returns the resume token for this session, which can later be
+	// passed to ResumableFS.ResumeUpload to continue an interrupted upload.
+	Token() string
+}
+
+// ResumableFS is implemented by drivers that support resumable/chunked
+// uploads: GCSStorage, S3Storage, and MemoryStorage.
+type ResumableFS interface {
+	// NewUploader starts a new resumable upload session for path.
+	NewUploader(path string, opts UploadOptions) (Uploader, error)
+
+	// ResumeUpload reattaches to an upload session previously returned by
+	// Uploader.Token, so an interrupted upload can continue.
+	ResumeUpload(token string) (Uploader, error)
+}
+
+const defaultChunkSize = 16 * 1024 * 1024 // 16 MiB, GCS's documented default
+
+// isRetryableStatus reports whether an HTTP status code from a chunk upload
+// should be retried: transient server errors and rate limiting.
+func isRetryableStatus(status int) bool {
+	return status == 429 || (status >= 500 && status < 600)
+}
+
+// backoff returns the delay before retry attempt n (0-indexed), exponential
+// with jitter, capped at 30s.
+func backoff(n int) time.Duration {
+	d := time.Duration(1<<uint(n)) * 200 * time.Millisecond
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d + time.Duration(rand.Int63n(int64(100*time.Millisecond)))
+}
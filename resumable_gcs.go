@@ -0,0 +1,258 @@
+package fsys
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	gcsChunkSizeUnit = 256 * 1024 // GCS requires chunk sizes in multiples of this
+	gcsMaxRetries    = 5
+)
+
+// NewUploader starts a resumable upload session per the GCS resumable-upload
+// protocol: https://cloud.google.com/storage/docs/resumable-uploads.
+func (gcs *GCSStorage) NewUploader(path string, opts UploadOptions) (Uploader, error) {
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = gcs.DefaultChunkSize
+	}
+	chunkSize = roundUpToChunk(chunkSize, gcsChunkSizeUnit, defaultChunkSize)
+
+	initURL := fmt.Sprintf(
+		"https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=resumable&name=%s",
+		url.PathEscape(gcs.BucketName), url.QueryEscape(gcs.key(path)),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, initURL, bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	if opts.ContentType != "" {
+		req.Header.Set("X-Upload-Content-Type", opts.ContentType)
+	}
+
+	resp, err := gcs.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("fsys: initiate resumable upload: %s: %s", resp.Status, body)
+	}
+
+	sessionURL := resp.Header.Get("Location")
+	if sessionURL == "" {
+		return nil, fmt.Errorf("fsys: resumable upload response missing Location header")
+	}
+
+	return &gcsUploader{
+		client:     gcs.httpClient,
+		sessionURL: sessionURL,
+		chunkSize:  chunkSize,
+		buf:        &bytes.Buffer{},
+	}, nil
+}
+
+// ResumeUpload reattaches to a session previously returned by
+// Uploader.Token, picking up from the offset GCS reports it has received.
+func (gcs *GCSStorage) ResumeUpload(token string) (Uploader, error) {
+	u := &gcsUploader{
+		client:     gcs.httpClient,
+		sessionURL: token,
+		chunkSize:  defaultChunkSize,
+		buf:        &bytes.Buffer{},
+	}
+
+	offset, done, err := u.queryStatus()
+	if err != nil {
+		return nil, err
+	}
+	u.offset = offset
+	u.committed = done
+	return u, nil
+}
+
+// gcsUploader drives the GCS resumable-upload protocol: buffer bytes until a
+// full chunk is ready, PUT it with a Content-Range header, and repeat. The
+// protocol is strictly sequential (each PUT is addressed by the byte offset
+// the previous one ended at), so unlike S3 parts, chunks can't be uploaded
+// concurrently; UploadOptions.MaxConcurrency has no effect on this driver.
+type gcsUploader struct {
+	client     *http.Client
+	sessionURL string
+	chunkSize  int64
+
+	buf       *bytes.Buffer
+	offset    int64
+	committed bool
+	aborted   bool
+}
+
+func (u *gcsUploader) Write(p []byte) (int, error) {
+	if u.committed || u.aborted {
+		return 0, fmt.Errorf("fsys: upload is already %s", u.state())
+	}
+
+	u.buf.Write(p)
+	for int64(u.buf.Len()) >= u.chunkSize {
+		chunk := u.buf.Next(int(u.chunkSize))
+		if err := u.putChunk(chunk, false); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (u *gcsUploader) Commit() error {
+	if u.committed {
+		return nil
+	}
+	if u.aborted {
+		return fmt.Errorf("fsys: upload was aborted")
+	}
+	if err := u.putChunk(u.buf.Bytes(), true); err != nil {
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+func (u *gcsUploader) Abort() error {
+	if u.committed || u.aborted {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodDelete, u.sessionURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	u.aborted = true
+	return nil
+}
+
+func (u *gcsUploader) Token() string {
+	return u.sessionURL
+}
+
+func (u *gcsUploader) state() string {
+	if u.committed {
+		return "committed"
+	}
+	return "aborted"
+}
+
+// putChunk PUTs chunk starting at u.offset, retrying transient failures with
+// exponential backoff. final marks this as the last chunk, making the total
+// object size known to GCS.
+func (u *gcsUploader) putChunk(chunk []byte, final bool) error {
+	start := u.offset
+	end := start + int64(len(chunk)) - 1
+
+	total := "*"
+	if final {
+		total = strconv.FormatInt(start+int64(len(chunk)), 10)
+	}
+
+	contentRange := fmt.Sprintf("bytes */%s", total)
+	if len(chunk) > 0 {
+		contentRange = fmt.Sprintf("bytes %d-%d/%s", start, end, total)
+	} else if !final {
+		return nil
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < gcsMaxRetries; attempt++ {
+		req, err := http.NewRequest(http.MethodPut, u.sessionURL, bytes.NewReader(chunk))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Range", contentRange)
+
+		resp, err := u.client.Do(req)
+		if err != nil {
+			lastErr = err
+			time.Sleep(backoff(attempt))
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == 308: // Resume Incomplete: chunk accepted, more to come
+			u.offset += int64(len(chunk))
+			return nil
+		case resp.StatusCode == 200 || resp.StatusCode == 201: // upload complete
+			u.offset += int64(len(chunk))
+			return nil
+		case isRetryableStatus(resp.StatusCode):
+			lastErr = fmt.Errorf("fsys: upload chunk: %s: %s", resp.Status, body)
+			time.Sleep(backoff(attempt))
+			continue
+		default:
+			return fmt.Errorf("fsys: upload chunk: %s: %s", resp.Status, body)
+		}
+	}
+	return fmt.Errorf("fsys: upload chunk failed after %d attempts: %w", gcsMaxRetries, lastErr)
+}
+
+// queryStatus asks GCS how many bytes of the session it has received, per
+// the resumable-upload status-check protocol (an empty PUT with a
+// "Content-Range: bytes */*" header).
+func (u *gcsUploader) queryStatus() (offset int64, done bool, err error) {
+	req, err := http.NewRequest(http.MethodPut, u.sessionURL, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Range", "bytes */*")
+	req.ContentLength = 0
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200, 201:
+		return 0, true, nil
+	case 308:
+		r := resp.Header.Get("Range")
+		if r == "" {
+			return 0, false, nil
+		}
+		parts := strings.SplitN(strings.TrimPrefix(r, "bytes=0-"), "-", 2)
+		received, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return received + 1, false, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return 0, false, fmt.Errorf("fsys: query upload status: %s: %s", resp.Status, body)
+	}
+}
+
+// roundUpToChunk rounds size up to the nearest multiple of unit, falling
+// back to def when size is zero.
+func roundUpToChunk(size, unit, def int64) int64 {
+	if size <= 0 {
+		return def
+	}
+	if size%unit == 0 {
+		return size
+	}
+	return ((size / unit) + 1) * unit
+}
@@ -0,0 +1,131 @@
+package fsys
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// memorySession is the state of one in-progress upload, keyed by token in
+// memoryCore.sessions so a MemoryStorage and its Sub'd copies share it.
+type memorySession struct {
+	key       string
+	buf       *bytes.Buffer
+	committed bool
+	aborted   bool
+}
+
+// memoryUploadSeq generates unique resume tokens for in-memory uploads.
+var memoryUploadSeq int64
+
+// NewUploader starts an in-memory upload session, so tests can exercise
+// resume/abort semantics without a network.
+func (ms *MemoryStorage) NewUploader(path string, opts UploadOptions) (Uploader, error) {
+	token := strconv.FormatInt(atomic.AddInt64(&memoryUploadSeq, 1), 10)
+
+	ms.core.mu.Lock()
+	defer ms.core.mu.Unlock()
+	if ms.core.sessions == nil {
+		ms.core.sessions = make(map[string]*memorySession)
+	}
+	sess := &memorySession{key: ms.key(path), buf: &bytes.Buffer{}}
+	ms.core.sessions[token] = sess
+
+	return &memoryUploader{ms: ms, token: token}, nil
+}
+
+// ResumeUpload reattaches to a session previously returned by
+// Uploader.Token.
+func (ms *MemoryStorage) ResumeUpload(token string) (Uploader, error) {
+	ms.core.mu.RLock()
+	_, exists := ms.core.sessions[token]
+	ms.core.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("fsys: no upload session for token %q", token)
+	}
+	return &memoryUploader{ms: ms, token: token}, nil
+}
+
+// memoryUploader is a thin handle onto a memorySession; the buffered bytes
+// live in the session so Write is visible to any other handle resumed from
+// the same token.
+type memoryUploader struct {
+	ms    *MemoryStorage
+	token string
+}
+
+func (u *memoryUploader) session() (*memorySession, error) {
+	u.ms.core.mu.Lock()
+	defer u.ms.core.mu.Unlock()
+	sess, exists := u.ms.core.sessions[u.token]
+	if !exists {
+		return nil, fmt.Errorf("fsys: no upload session for token %q", u.token)
+	}
+	return sess, nil
+}
+
+func (u *memoryUploader) Write(p []byte) (int, error) {
+	sess, err := u.session()
+	if err != nil {
+		return 0, err
+	}
+
+	u.ms.core.mu.Lock()
+	defer u.ms.core.mu.Unlock()
+	if sess.committed || sess.aborted {
+		return 0, fmt.Errorf("fsys: upload is already %s", sessionState(sess))
+	}
+	return sess.buf.Write(p)
+}
+
+func (u *memoryUploader) Commit() error {
+	sess, err := u.session()
+	if err != nil {
+		return err
+	}
+
+	u.ms.core.mu.Lock()
+	defer u.ms.core.mu.Unlock()
+	if sess.committed {
+		return nil
+	}
+	if sess.aborted {
+		return fmt.Errorf("fsys: upload was aborted")
+	}
+
+	u.ms.core.files[sess.key] = &File{
+		Name:    sess.key,
+		Content: bytes.NewBuffer(sess.buf.Bytes()),
+		ModTime: time.Now(),
+	}
+	sess.committed = true
+	return nil
+}
+
+func (u *memoryUploader) Abort() error {
+	sess, err := u.session()
+	if err != nil {
+		return err
+	}
+
+	u.ms.core.mu.Lock()
+	defer u.ms.core.mu.Unlock()
+	if sess.committed || sess.aborted {
+		return nil
+	}
+	sess.aborted = true
+	return nil
+}
+
+func (u *memoryUploader) Token() string {
+	return u.token
+}
+
+func sessionState(sess *memorySession) string {
+	if sess.committed {
+		return "committed"
+	}
+	return "aborted"
+}
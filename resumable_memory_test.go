@@ -0,0 +1,77 @@
+package fsys
+
+import (
+	"io"
+	"testing"
+)
+
+func TestMemoryStorageResumableUpload(t *testing.T) {
+	ms := NewMemoryStorage()
+
+	up, err := ms.NewUploader("big.bin", UploadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := up.Write([]byte("hello, ")); err != nil {
+		t.Fatal(err)
+	}
+
+	token := up.Token()
+	resumed, err := ms.ResumeUpload(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resumed.Write([]byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resumed.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := ms.Read("big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello, world" {
+		t.Errorf("Read() = %q, want %q", got, "hello, world")
+	}
+}
+
+func TestMemoryStorageResumableUploadAbort(t *testing.T) {
+	ms := NewMemoryStorage()
+
+	up, err := ms.NewUploader("scratch.bin", UploadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := up.Write([]byte("discard me")); err != nil {
+		t.Fatal(err)
+	}
+	if err := up.Abort(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := up.Write([]byte("too late")); err == nil {
+		t.Error("Write() after Abort() should fail")
+	}
+	if err := up.Commit(); err == nil {
+		t.Error("Commit() after Abort() should fail")
+	}
+	if exists, _ := ms.Exists("scratch.bin"); exists {
+		t.Error("aborted upload should not have created scratch.bin")
+	}
+}
+
+func TestMemoryStorageResumeUploadUnknownToken(t *testing.T) {
+	ms := NewMemoryStorage()
+	if _, err := ms.ResumeUpload("does-not-exist"); err == nil {
+		t.Error("ResumeUpload() with an unknown token should fail")
+	}
+}
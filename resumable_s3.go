@@ -0,0 +1,319 @@
+package fsys
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const (
+	s3MinPartSize = 5 * 1024 * 1024 // S3 rejects non-final parts smaller than this
+	s3MaxRetries  = 5
+)
+
+// NewUploader starts an S3 multipart upload: https://docs.aws.amazon.com/AmazonS3/latest/userguide/qfacts.html.
+func (s3st *S3Storage) NewUploader(path string, opts UploadOptions) (Uploader, error) {
+	partSize := opts.ChunkSize
+	if partSize <= 0 {
+		partSize = s3st.DefaultChunkSize
+	}
+	if partSize < s3MinPartSize {
+		partSize = defaultChunkSize
+	}
+
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = s3st.DefaultMaxConcurrency
+	}
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	ctx := context.Background()
+	in := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(s3st.key(path)),
+	}
+	if opts.ContentType != "" {
+		in.ContentType = aws.String(opts.ContentType)
+	}
+
+	out, err := s3st.Client.CreateMultipartUpload(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Uploader{
+		client:   s3st.Client,
+		bucket:   s3st.BucketName,
+		key:      s3st.key(path),
+		uploadID: aws.ToString(out.UploadId),
+		partSize: partSize,
+		sem:      make(chan struct{}, maxConcurrency),
+		buf:      &bytes.Buffer{},
+		nextPart: 1,
+	}, nil
+}
+
+// ResumeUpload reattaches to a multipart upload previously returned by
+// Uploader.Token, fetching the parts S3 already has so Commit can finalize
+// the object without re-uploading them.
+func (s3st *S3Storage) ResumeUpload(token string) (Uploader, error) {
+	bucket, key, uploadID, err := parseS3Token(token)
+	if err != nil {
+		return nil, err
+	}
+	if bucket != s3st.BucketName {
+		return nil, fmt.Errorf("fsys: resume token is for bucket %q, not %q", bucket, s3st.BucketName)
+	}
+
+	ctx := context.Background()
+	u := &s3Uploader{
+		client:   s3st.Client,
+		bucket:   bucket,
+		key:      key,
+		uploadID: uploadID,
+		partSize: defaultChunkSize,
+		sem:      make(chan struct{}, 1),
+		buf:      &bytes.Buffer{},
+		nextPart: 1,
+	}
+
+	var marker *string
+	for {
+		out, err := s3st.Client.ListParts(ctx, &s3.ListPartsInput{
+			Bucket:           aws.String(bucket),
+			Key:              aws.String(key),
+			UploadId:         aws.String(uploadID),
+			PartNumberMarker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range out.Parts {
+			u.parts = append(u.parts, types.CompletedPart{ETag: p.ETag, PartNumber: p.PartNumber})
+			u.nextPart = aws.ToInt32(p.PartNumber) + 1
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		marker = out.NextPartNumberMarker
+	}
+
+	return u, nil
+}
+
+// s3Uploader drives an S3 multipart upload: buffer bytes until a full part
+// is ready, UploadPart it, and finalize with CompleteMultipartUpload. Parts
+// are independent objects in the multipart API, so up to cap(sem) of them
+// may be in flight to S3 at once.
+type s3Uploader struct {
+	client      *s3.Client
+	bucket, key string
+	uploadID    string
+	partSize    int64
+	sem         chan struct{}
+
+	buf      *bytes.Buffer
+	nextPart int32
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	parts    []types.CompletedPart
+	firstErr error
+
+	committed bool
+	aborted   bool
+}
+
+func (u *s3Uploader) Write(p []byte) (int, error) {
+	if u.committed || u.aborted {
+		return 0, fmt.Errorf("fsys: upload is already %s", u.state())
+	}
+	if err := u.err(); err != nil {
+		return 0, err
+	}
+
+	u.buf.Write(p)
+	for int64(u.buf.Len()) >= u.partSize {
+		part := make([]byte, u.partSize)
+		copy(part, u.buf.Next(int(u.partSize)))
+		if err := u.dispatchPart(part); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (u *s3Uploader) Commit() error {
+	if u.committed {
+		return nil
+	}
+	if u.aborted {
+		return fmt.Errorf("fsys: upload was aborted")
+	}
+	if u.buf.Len() > 0 {
+		part := make([]byte, u.buf.Len())
+		copy(part, u.buf.Bytes())
+		u.buf.Reset()
+		if err := u.dispatchPart(part); err != nil {
+			return err
+		}
+	}
+
+	u.wg.Wait()
+	if err := u.err(); err != nil {
+		return err
+	}
+
+	sort.Slice(u.parts, func(i, j int) bool {
+		return aws.ToInt32(u.parts[i].PartNumber) < aws.ToInt32(u.parts[j].PartNumber)
+	})
+
+	ctx := context.Background()
+	_, err := u.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        aws.String(u.uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: u.parts},
+	})
+	if err != nil {
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+func (u *s3Uploader) Abort() error {
+	if u.committed || u.aborted {
+		return nil
+	}
+	u.wg.Wait()
+
+	ctx := context.Background()
+	_, err := u.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(u.key),
+		UploadId: aws.String(u.uploadID),
+	})
+	if err != nil {
+		return err
+	}
+	u.aborted = true
+	return nil
+}
+
+func (u *s3Uploader) Token() string {
+	return formatS3Token(u.bucket, u.key, u.uploadID)
+}
+
+func (u *s3Uploader) state() string {
+	if u.committed {
+		return "committed"
+	}
+	return "aborted"
+}
+
+// dispatchPart assigns the next part number to part and uploads it in a
+// goroutine, blocking first if cap(u.sem) uploads are already in flight so
+// at most that many parts are ever outstanding at once.
+func (u *s3Uploader) dispatchPart(part []byte) error {
+	if err := u.err(); err != nil {
+		return err
+	}
+
+	partNumber := u.nextPart
+	u.nextPart++
+
+	u.sem <- struct{}{}
+	u.wg.Add(1)
+	go func() {
+		defer u.wg.Done()
+		defer func() { <-u.sem }()
+
+		completed, err := u.uploadPart(partNumber, part)
+
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if err != nil {
+			if u.firstErr == nil {
+				u.firstErr = err
+			}
+			return
+		}
+		u.parts = append(u.parts, completed)
+	}()
+	return nil
+}
+
+// err returns the first error recorded by a part upload, if any.
+func (u *s3Uploader) err() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.firstErr
+}
+
+// uploadPart sends part as partNumber, retrying transient failures with
+// exponential backoff.
+func (u *s3Uploader) uploadPart(partNumber int32, part []byte) (types.CompletedPart, error) {
+	ctx := context.Background()
+	var lastErr error
+	for attempt := 0; attempt < s3MaxRetries; attempt++ {
+		out, err := u.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(u.bucket),
+			Key:        aws.String(u.key),
+			UploadId:   aws.String(u.uploadID),
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(part),
+		})
+		if err == nil {
+			return types.CompletedPart{ETag: out.ETag, PartNumber: aws.Int32(partNumber)}, nil
+		}
+
+		lastErr = err
+		if !isRetryableS3Error(err) {
+			return types.CompletedPart{}, err
+		}
+		time.Sleep(backoff(attempt))
+	}
+	return types.CompletedPart{}, fmt.Errorf("fsys: upload part %d failed after %d attempts: %w", partNumber, s3MaxRetries, lastErr)
+}
+
+// isRetryableS3Error reports whether err carries a transient HTTP status
+// (5xx or 429) worth retrying.
+func isRetryableS3Error(err error) bool {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		return isRetryableStatus(respErr.HTTPStatusCode())
+	}
+	return false
+}
+
+// formatS3Token and parseS3Token encode the (bucket, key, uploadID) triple
+// a resume needs into the single string Uploader.Token returns.
+func formatS3Token(bucket, key, uploadID string) string {
+	v := url.Values{"bucket": {bucket}, "key": {key}, "uploadId": {uploadID}}
+	return v.Encode()
+}
+
+func parseS3Token(token string) (bucket, key, uploadID string, err error) {
+	v, err := url.ParseQuery(token)
+	if err != nil {
+		return "", "", "", fmt.Errorf("fsys: invalid resume token: %w", err)
+	}
+	bucket, key, uploadID = v.Get("bucket"), v.Get("key"), v.Get("uploadId")
+	if bucket == "" || key == "" || uploadID == "" {
+		return "", "", "", errors.New("fsys: invalid resume token: missing bucket, key, or uploadId")
+	}
+	return bucket, key, uploadID, nil
+}
@@ -0,0 +1,634 @@
+package fsys
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Storage is an implementation of FS for Amazon S3.
+type S3Storage struct {
+	// S3 bucket name
+	BucketName string
+
+	// Region the bucket lives in
+	Region string
+
+	// S3 client
+	Client *s3.Client
+
+	// prefix is prepended to every path, so an S3Storage returned by Sub
+	// behaves as if rooted at that prefix within the bucket.
+	prefix string
+
+	// DefaultChunkSize and DefaultMaxConcurrency seed UploadOptions.ChunkSize
+	// and UploadOptions.MaxConcurrency for NewUploader calls that leave them
+	// zero. Set by the DRIVER_S3 factory; direct callers of NewS3Storage can
+	// set them after construction.
+	DefaultChunkSize      int64
+	DefaultMaxConcurrency int
+
+	// DefaultTimeout bounds how long a Context-suffixed call may run when
+	// the ctx passed in carries no deadline of its own. Zero means no
+	// default: the call runs until ctx is cancelled or the request
+	// completes.
+	DefaultTimeout time.Duration
+}
+
+// withTimeout applies s3st.DefaultTimeout to ctx if set and ctx doesn't
+// already carry a deadline. The returned cancel func must be deferred by
+// the caller even when it's a no-op.
+func (s3st *S3Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s3st.DefaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s3st.DefaultTimeout)
+}
+
+func NewS3Storage(bucket, region string) (*S3Storage, error) {
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{
+		BucketName: bucket,
+		Region:     region,
+		Client:     s3.NewFromConfig(cfg),
+	}, nil
+}
+
+// NewS3StorageFromEnv builds an S3Storage from the default AWS config chain
+// (environment, shared config, instance role) without an explicit region.
+// It's used by FromURL, where an s3:// URL carries no room for a region.
+func NewS3StorageFromEnv(ctx context.Context, bucket string) (*S3Storage, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{
+		BucketName: bucket,
+		Region:     cfg.Region,
+		Client:     s3.NewFromConfig(cfg),
+	}, nil
+}
+
+func (s3st *S3Storage) Driver() string {
+	return DRIVER_S3
+}
+
+// key returns path prefixed with s3st.prefix, as set up by Sub. "." is
+// normalized to "" first, so it resolves to the root (s3st.prefix itself)
+// instead of a literal path segment, the same as fs.ValidPath treats "." as
+// the name of the tree's root.
+func (s3st *S3Storage) key(p string) string {
+	if p == "." {
+		p = ""
+	}
+	if s3st.prefix == "" {
+		return p
+	}
+	return path.Join(s3st.prefix, p)
+}
+
+func (s3st *S3Storage) Read(path string) (io.ReadCloser, error) {
+	return s3st.ReadContext(context.Background(), path)
+}
+
+// ReadContext is Read, threading ctx through to the underlying S3 call so a
+// caller-supplied deadline, cancellation, or tracing span propagates.
+func (s3st *S3Storage) ReadContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, cancel := s3st.withTimeout(ctx)
+	defer cancel()
+
+	out, err := s3st.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(s3st.key(path)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s3st *S3Storage) Write(path string, contents []byte) error {
+	return s3st.WriteContext(context.Background(), path, contents)
+}
+
+// WriteContext is Write, threading ctx through to the underlying S3 call.
+func (s3st *S3Storage) WriteContext(ctx context.Context, path string, contents []byte) error {
+	ctx, cancel := s3st.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s3st.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(s3st.key(path)),
+		Body:   bytes.NewReader(contents),
+	})
+	return err
+}
+
+func (s3st *S3Storage) Delete(path string) error {
+	return s3st.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is Delete, threading ctx through to the underlying S3 call.
+func (s3st *S3Storage) DeleteContext(ctx context.Context, path string) error {
+	ctx, cancel := s3st.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s3st.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(s3st.key(path)),
+	})
+	return err
+}
+
+func (s3st *S3Storage) Exists(path string) (bool, error) {
+	return s3st.ExistsContext(context.Background(), path)
+}
+
+// ExistsContext is Exists, threading ctx through to the underlying S3 call.
+func (s3st *S3Storage) ExistsContext(ctx context.Context, path string) (bool, error) {
+	ctx, cancel := s3st.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s3st.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(s3st.key(path)),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s3st *S3Storage) Rename(oldPath, newPath string) error {
+	if err := s3st.Copy(oldPath, newPath); err != nil {
+		return err
+	}
+	return s3st.Delete(oldPath)
+}
+
+func (s3st *S3Storage) Copy(sourcePath, destPath string) error {
+	return s3st.CopyContext(context.Background(), sourcePath, destPath)
+}
+
+// CopyContext is Copy, threading ctx through to the underlying S3 call.
+func (s3st *S3Storage) CopyContext(ctx context.Context, sourcePath, destPath string) error {
+	ctx, cancel := s3st.withTimeout(ctx)
+	defer cancel()
+
+	source := url.PathEscape(fmt.Sprintf("%s/%s", s3st.BucketName, s3st.key(sourcePath)))
+	_, err := s3st.Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s3st.BucketName),
+		CopySource: aws.String(source),
+		Key:        aws.String(s3st.key(destPath)),
+	})
+	return err
+}
+
+func (s3st *S3Storage) CreateDirectory(path string) error {
+	ctx := context.Background()
+	_, err := s3st.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(strings.TrimSuffix(s3st.key(path), "/") + "/"),
+	})
+	return err
+}
+
+func (s3st *S3Storage) GetUrl(path string) (string, error) {
+	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s3st.BucketName, s3st.key(path)), nil
+}
+
+// SignedUrl returns a presigned URL for path, scoped to opts.Method (GET or
+// PUT) and valid for opts.Expires, using the AWS SDK's presigner.
+func (s3st *S3Storage) SignedUrl(path string, opts SignOptions) (string, error) {
+	ctx := context.Background()
+	presigner := s3.NewPresignClient(s3st.Client)
+	withExpires := s3.WithPresignExpires(opts.Expires)
+
+	switch strings.ToUpper(opts.Method) {
+	case "", http.MethodGet:
+		in := &s3.GetObjectInput{
+			Bucket: aws.String(s3st.BucketName),
+			Key:    aws.String(s3st.key(path)),
+		}
+		if opts.ResponseContentDisposition != "" {
+			in.ResponseContentDisposition = aws.String(opts.ResponseContentDisposition)
+		}
+		req, err := presigner.PresignGetObject(ctx, in, withExpires)
+		if err != nil {
+			return "", err
+		}
+		return req.URL, nil
+
+	case http.MethodPut:
+		in := &s3.PutObjectInput{
+			Bucket: aws.String(s3st.BucketName),
+			Key:    aws.String(s3st.key(path)),
+		}
+		if opts.ContentType != "" {
+			in.ContentType = aws.String(opts.ContentType)
+		}
+		if opts.ContentMD5 != "" {
+			in.ContentMD5 = aws.String(opts.ContentMD5)
+		}
+		req, err := presigner.PresignPutObject(ctx, in, withExpires)
+		if err != nil {
+			return "", err
+		}
+		return req.URL, nil
+
+	default:
+		return "", fmt.Errorf("fsys: unsupported signed URL method %q", opts.Method)
+	}
+}
+
+// OpenFile opens a file, staging its contents into a temp *os.File since S3
+// has no local file handle to hand back.
+func (s3st *S3Storage) OpenFile(path string) (*os.File, error) {
+	rc, err := s3st.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	tempFile, err := os.CreateTemp("", "s3_temp_*")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(tempFile, rc); err != nil {
+		tempFile.Close()
+		return nil, err
+	}
+
+	if _, err := tempFile.Seek(0, 0); err != nil {
+		tempFile.Close()
+		return nil, err
+	}
+
+	return tempFile, nil
+}
+
+func (s3st *S3Storage) Upload(file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	return s3st.UploadContext(context.Background(), file, header, dir)
+}
+
+// UploadContext is Upload, threading ctx through to the underlying S3 call.
+func (s3st *S3Storage) UploadContext(ctx context.Context, file multipart.File, header *multipart.FileHeader, dir string) (*os.File, error) {
+	ctx, cancel := s3st.withTimeout(ctx)
+	defer cancel()
+
+	objectPath := fmt.Sprintf("%s/%s", dir, header.Filename)
+
+	_, err := s3st.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(s3st.key(objectPath)),
+		Body:   file,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s3st.OpenFile(objectPath)
+}
+
+// Open implements fs.FS, returning an fs.File rather than an *os.File so S3
+// can be used with fs.WalkDir, text/template.ParseFS, http.FS, etc. Opening
+// the root ("." or "") returns a synthetic directory file backed by
+// ReadDir, since there's no object literally stored under that name.
+func (s3st *S3Storage) Open(name string) (fs.File, error) {
+	key := s3st.key(name)
+	if key == s3st.prefix {
+		entries, err := s3st.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: &fileInfo{name: ".", isDir: true}, entries: entries}, nil
+	}
+
+	ctx := context.Background()
+	out, err := s3st.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, err
+	}
+
+	info := &fileInfo{name: path.Base(name)}
+	if out.ContentLength != nil {
+		info.size = *out.ContentLength
+	}
+	if out.LastModified != nil {
+		info.modTime = *out.LastModified
+	}
+
+	return &openFile{ReadCloser: out.Body, info: info}, nil
+}
+
+// Stat implements fs.StatFS. The root ("." or "") is synthesized as a
+// directory, since there's no object literally stored under that name.
+func (s3st *S3Storage) Stat(name string) (fs.FileInfo, error) {
+	key := s3st.key(name)
+	if key == s3st.prefix {
+		return &fileInfo{name: ".", isDir: true}, nil
+	}
+
+	ctx := context.Background()
+	out, err := s3st.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s3st.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, err
+	}
+
+	info := &fileInfo{name: path.Base(name)}
+	if out.ContentLength != nil {
+		info.size = *out.ContentLength
+	}
+	if out.LastModified != nil {
+		info.modTime = *out.LastModified
+	}
+	return info, nil
+}
+
+// ReadDir implements fs.ReadDirFS by listing objects one level below name,
+// using S3's delimiter support to fold deeper keys into synthetic directory
+// entries.
+func (s3st *S3Storage) ReadDir(name string) ([]fs.DirEntry, error) {
+	ctx := context.Background()
+	prefix := s3st.key(name)
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+
+	var entries []fs.DirEntry
+	var token *string
+	for {
+		out, err := s3st.Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(s3st.BucketName),
+			Prefix:            aws.String(prefix),
+			Delimiter:         aws.String("/"),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cp := range out.CommonPrefixes {
+			entries = append(entries, &dirEntry{info: &fileInfo{
+				name:  strings.TrimSuffix(strings.TrimPrefix(aws.ToString(cp.Prefix), prefix), "/"),
+				isDir: true,
+			}})
+		}
+		for _, obj := range out.Contents {
+			key := aws.ToString(obj.Key)
+			if key == prefix {
+				continue
+			}
+			info := &fileInfo{name: strings.TrimPrefix(key, prefix)}
+			if obj.Size != nil {
+				info.size = *obj.Size
+			}
+			if obj.LastModified != nil {
+				info.modTime = *obj.LastModified
+			}
+			entries = append(entries, &dirEntry{info: info})
+		}
+
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		token = out.NextContinuationToken
+	}
+	return entries, nil
+}
+
+// Glob implements fs.GlobFS.
+func (s3st *S3Storage) Glob(pattern string) ([]string, error) {
+	return globByReadDir(s3st.ReadDir, s3st.Stat, pattern)
+}
+
+// Sub implements fs.SubFS, returning an S3Storage rooted at dir within the
+// same bucket.
+func (s3st *S3Storage) Sub(dir string) (fs.FS, error) {
+	return &S3Storage{
+		BucketName:            s3st.BucketName,
+		Region:                s3st.Region,
+		Client:                s3st.Client,
+		prefix:                s3st.key(dir),
+		DefaultChunkSize:      s3st.DefaultChunkSize,
+		DefaultMaxConcurrency: s3st.DefaultMaxConcurrency,
+		DefaultTimeout:        s3st.DefaultTimeout,
+	}, nil
+}
+
+// Create implements CreateFS, handing back a writer that commits the object
+// on Close.
+func (s3st *S3Storage) Create(name string) (WriterFile, error) {
+	return &s3Writer{s3st: s3st, name: name, buf: &bytes.Buffer{}}, nil
+}
+
+// s3Writer buffers writes and commits them with a single PutObject on Close,
+// since the S3 API has no incremental-append write operation.
+type s3Writer struct {
+	s3st *S3Storage
+	name string
+	buf  *bytes.Buffer
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	return w.s3st.Write(w.name, w.buf.Bytes())
+}
+
+// relativeName strips s3st.prefix from key, so List reports paths relative
+// to this S3Storage's own root the same way key() makes incoming paths
+// relative to it.
+func (s3st *S3Storage) relativeName(key string) string {
+	if s3st.prefix == "" {
+		return key
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(key, s3st.prefix), "/")
+}
+
+// List returns every object whose key starts with prefix, recursively,
+// using an undelimited ListObjectsV2 query. The prefix is treated as a
+// directory boundary, the same as ReadDir: "reports" matches
+// "reports/a.txt" but not the unrelated sibling key "reports-archive/b.txt".
+// A prefix naming an exact object, e.g. "reports/a.txt", matches that one
+// object, the same as LocalStorage's filepath.WalkDir does when pointed at
+// a file instead of a directory.
+func (s3st *S3Storage) List(prefix string) ([]FileInfo, error) {
+	ctx := context.Background()
+	p := s3st.key(prefix)
+
+	var out []FileInfo
+	var token *string
+	for {
+		res, err := s3st.Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(s3st.BucketName),
+			Prefix:            aws.String(p),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range res.Contents {
+			key := aws.ToString(obj.Key)
+			if !matchesPrefixBoundary(key, p) {
+				continue
+			}
+			fi := FileInfo{Name: s3st.relativeName(key)}
+			if obj.Size != nil {
+				fi.Size = *obj.Size
+			}
+			if obj.LastModified != nil {
+				fi.ModTime = *obj.LastModified
+			}
+			if obj.ETag != nil {
+				fi.ETag = strings.Trim(*obj.ETag, `"`)
+			}
+			out = append(out, fi)
+		}
+
+		if !aws.ToBool(res.IsTruncated) {
+			break
+		}
+		token = res.NextContinuationToken
+	}
+	return out, nil
+}
+
+// Walk walks the tree rooted at root, calling fn once per directory and
+// file, synthesizing directories from key prefixes the same way ReadDir
+// does.
+func (s3st *S3Storage) Walk(root string, fn WalkFunc) error {
+	return walkByReadDir(s3st.ReadDir, root, fn)
+}
+
+// DeletePrefix deletes every object whose key starts with prefix,
+// recursively, batching deletes up to S3's 1000-key-per-request limit. The
+// prefix is treated as a directory boundary, the same as ReadDir: "reports"
+// matches "reports/a.txt" but not the unrelated sibling key
+// "reports-archive/b.txt". A prefix naming an exact object matches that one
+// object, the same as List.
+func (s3st *S3Storage) DeletePrefix(prefix string) error {
+	ctx := context.Background()
+	p := s3st.key(prefix)
+
+	var token *string
+	for {
+		res, err := s3st.Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(s3st.BucketName),
+			Prefix:            aws.String(p),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return err
+		}
+
+		var ids []types.ObjectIdentifier
+		for _, obj := range res.Contents {
+			if !matchesPrefixBoundary(aws.ToString(obj.Key), p) {
+				continue
+			}
+			ids = append(ids, types.ObjectIdentifier{Key: obj.Key})
+		}
+		if len(ids) > 0 {
+			_, err := s3st.Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(s3st.BucketName),
+				Delete: &types.Delete{Objects: ids},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !aws.ToBool(res.IsTruncated) {
+			break
+		}
+		token = res.NextContinuationToken
+	}
+	return nil
+}
+
+// S3Parameters documents the New/Register params for DRIVER_S3.
+type S3Parameters struct {
+	// Bucket is the S3 bucket name (required).
+	Bucket string
+
+	// Region is the AWS region the bucket lives in (required).
+	Region string
+
+	// ChunkSize and MaxConcurrency seed UploadOptions defaults for
+	// NewUploader calls that leave them zero (optional).
+	ChunkSize      int64
+	MaxConcurrency int
+
+	// Timeout bounds Context-suffixed calls made with a ctx that carries no
+	// deadline of its own (optional).
+	Timeout time.Duration
+}
+
+func init() {
+	Register(DRIVER_S3, func(params map[string]any) (FS, error) {
+		bucket, err := stringParam(DRIVER_S3, params, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		region, err := stringParam(DRIVER_S3, params, "region")
+		if err != nil {
+			return nil, err
+		}
+
+		s3st, err := NewS3Storage(bucket, region)
+		if err != nil {
+			return nil, err
+		}
+		s3st.DefaultChunkSize = optionalInt64Param(params, "chunkSize")
+		s3st.DefaultMaxConcurrency = optionalIntParam(params, "maxConcurrency")
+		s3st.DefaultTimeout = optionalDurationParam(params, "timeout")
+		return s3st, nil
+	})
+}
@@ -0,0 +1,57 @@
+package fsys
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SignOptions configures a signed, time-limited URL produced by
+// FS.SignedUrl.
+type SignOptions struct {
+	// Method is the HTTP method the URL is scoped to: GET or PUT. Empty
+	// means GET.
+	Method string
+
+	// Expires is how long the URL remains valid, measured from the moment
+	// SignedUrl is called.
+	Expires time.Duration
+
+	// ContentType, if set, binds a PUT URL to this Content-Type: the
+	// backend rejects an upload whose Content-Type header doesn't match.
+	ContentType string
+
+	// ContentMD5, if set, binds a PUT URL to this base64-encoded MD5
+	// digest of the object body.
+	ContentMD5 string
+
+	// ResponseContentDisposition, if set, overrides the Content-Disposition
+	// header a GET URL responds with (e.g. to force a download filename).
+	ResponseContentDisposition string
+}
+
+// mockSignedUrl builds a signed-looking URL for drivers with no real
+// signing backend (local, memory), encoding the same parameters a real
+// signed URL would carry so callers can exercise signing semantics without
+// a cloud account.
+func mockSignedUrl(base string, opts SignOptions) string {
+	method := opts.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	v := url.Values{"X-Method": {method}}
+	if opts.Expires > 0 {
+		v.Set("X-Expires", time.Now().Add(opts.Expires).UTC().Format(time.RFC3339))
+	}
+	if opts.ContentType != "" {
+		v.Set("X-Content-Type", opts.ContentType)
+	}
+	if opts.ContentMD5 != "" {
+		v.Set("X-Content-MD5", opts.ContentMD5)
+	}
+	if opts.ResponseContentDisposition != "" {
+		v.Set("response-content-disposition", opts.ResponseContentDisposition)
+	}
+	return base + "?" + v.Encode()
+}
@@ -0,0 +1,98 @@
+package fsys
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestMockSignedUrlDefaultsToGet(t *testing.T) {
+	got := mockSignedUrl("mem://a.txt", SignOptions{})
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method := u.Query().Get("X-Method"); method != http.MethodGet {
+		t.Errorf("X-Method = %q, want %q", method, http.MethodGet)
+	}
+}
+
+func TestMockSignedUrlEncodesBindings(t *testing.T) {
+	opts := SignOptions{
+		Method:                     http.MethodPut,
+		Expires:                    time.Hour,
+		ContentType:                "text/plain",
+		ContentMD5:                 "deadbeef",
+		ResponseContentDisposition: "attachment; filename=report.txt",
+	}
+	got := mockSignedUrl("mem://a.txt", opts)
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("X-Method") != http.MethodPut {
+		t.Errorf("X-Method = %q, want %q", q.Get("X-Method"), http.MethodPut)
+	}
+	if q.Get("X-Expires") == "" {
+		t.Error("X-Expires should be set when Expires > 0")
+	}
+	if q.Get("X-Content-Type") != opts.ContentType {
+		t.Errorf("X-Content-Type = %q, want %q", q.Get("X-Content-Type"), opts.ContentType)
+	}
+	if q.Get("X-Content-MD5") != opts.ContentMD5 {
+		t.Errorf("X-Content-MD5 = %q, want %q", q.Get("X-Content-MD5"), opts.ContentMD5)
+	}
+	if q.Get("response-content-disposition") != opts.ResponseContentDisposition {
+		t.Errorf("response-content-disposition = %q, want %q", q.Get("response-content-disposition"), opts.ResponseContentDisposition)
+	}
+}
+
+func TestMemoryStorageSignedUrlRequiresExistenceForGet(t *testing.T) {
+	ms := NewMemoryStorage()
+
+	if _, err := ms.SignedUrl("missing.txt", SignOptions{Method: http.MethodGet}); err == nil {
+		t.Error(`SignedUrl(missing.txt, GET) should fail when the file doesn't exist`)
+	}
+
+	if err := ms.Write("present.txt", []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ms.SignedUrl("present.txt", SignOptions{Method: http.MethodGet}); err != nil {
+		t.Errorf("SignedUrl(present.txt, GET) = %v, want nil", err)
+	}
+}
+
+func TestMemoryStorageSignedUrlAllowsPutForMissingFile(t *testing.T) {
+	ms := NewMemoryStorage()
+
+	got, err := ms.SignedUrl("new.txt", SignOptions{Method: http.MethodPut})
+	if err != nil {
+		t.Fatalf("SignedUrl(new.txt, PUT) = %v, want nil", err)
+	}
+	if got == "" {
+		t.Error("SignedUrl(new.txt, PUT) returned an empty URL")
+	}
+}
+
+func TestLocalStorageSignedUrl(t *testing.T) {
+	ls, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ls.SignedUrl("missing.txt", SignOptions{Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("SignedUrl(missing.txt, GET) = %v, want nil", err)
+	}
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method := u.Query().Get("X-Method"); method != http.MethodGet {
+		t.Errorf("X-Method = %q, want %q", method, http.MethodGet)
+	}
+}